@@ -0,0 +1,160 @@
+package tinta
+
+// Anchor tells [CanvasStyle.Place] where to put a layer: at a point of
+// the canvas frame, such as [AnchorCenter], or next to another named
+// layer, with [RightOf], [LeftOf], [Below] or [Above].
+type Anchor struct {
+	// h and v select the point of the frame the layer is aligned to.
+	h, v Align
+	// ref names the layer the placement is relative to, if any; side is
+	// one of the directions below and gap the space left in between.
+	ref  string
+	side int
+	gap  int
+}
+
+const (
+	sideRight = iota + 1
+	sideLeft
+	sideBelow
+	sideAbove
+)
+
+// Anchors at the corners, edge midpoints and center of the canvas frame.
+var (
+	AnchorTopLeft     = Anchor{h: AlignLeft, v: AlignTop}
+	AnchorTop         = Anchor{h: AlignCenter, v: AlignTop}
+	AnchorTopRight    = Anchor{h: AlignRight, v: AlignTop}
+	AnchorLeft        = Anchor{h: AlignLeft, v: AlignMiddle}
+	AnchorCenter      = Anchor{h: AlignCenter, v: AlignMiddle}
+	AnchorRight       = Anchor{h: AlignRight, v: AlignMiddle}
+	AnchorBottomLeft  = Anchor{h: AlignLeft, v: AlignBottom}
+	AnchorBottom      = Anchor{h: AlignCenter, v: AlignBottom}
+	AnchorBottomRight = Anchor{h: AlignRight, v: AlignBottom}
+)
+
+// RightOf places a layer gap columns to the right of the layer named id,
+// with their top edges aligned.
+func RightOf(id string, gap int) Anchor {
+	return Anchor{ref: id, side: sideRight, gap: gap}
+}
+
+// LeftOf places a layer gap columns to the left of the layer named id,
+// with their top edges aligned.
+func LeftOf(id string, gap int) Anchor {
+	return Anchor{ref: id, side: sideLeft, gap: gap}
+}
+
+// Below places a layer gap rows below the layer named id, with their left
+// edges aligned.
+func Below(id string, gap int) Anchor {
+	return Anchor{ref: id, side: sideBelow, gap: gap}
+}
+
+// Above places a layer gap rows above the layer named id, with their left
+// edges aligned.
+func Above(id string, gap int) Anchor {
+	return Anchor{ref: id, side: sideAbove, gap: gap}
+}
+
+// Place adds a rendered string positioned by anchor, then shifted by
+// (dx, dy). Frame anchors are resolved when the canvas is rendered,
+// against its fixed size or, when auto-sizing, against the area covered
+// by the layers added with absolute positions. Relative anchors follow
+// the named layer wherever it ends up; when no earlier layer has that
+// name, the layer is placed at the top-left of the frame.
+func (c *CanvasStyle) Place(s string, anchor Anchor, dx, dy int) *CanvasStyle {
+	return c.PlaceNamed("", s, anchor, dx, dy)
+}
+
+// PlaceNamed is like [CanvasStyle.Place] and names the layer id.
+func (c *CanvasStyle) PlaceNamed(id, s string, anchor Anchor, dx, dy int) *CanvasStyle {
+	return c.addLayer(layer{id: id, grid: c.parse(s), x: dx, y: dy, z: c.nextZ, anchor: &anchor})
+}
+
+// size returns the width and height of the layer's grid.
+func (ly layer) size() (w, h int) {
+	for _, row := range ly.grid {
+		if len(row) > w {
+			w = len(row)
+		}
+	}
+	return w, len(ly.grid)
+}
+
+// resolve returns the layers with anchored positions turned into absolute
+// ones, in insertion order.
+func (c *CanvasStyle) resolve() []layer {
+	out := make([]layer, len(c.layers))
+	copy(out, c.layers)
+
+	// The frame spans the absolute layers, from the origin or the most
+	// negative coordinate, unless the canvas has a fixed size.
+	minX, minY, maxX, maxY := 0, 0, -1, -1
+	for _, ly := range out {
+		if ly.anchor != nil || ly.hidden {
+			continue
+		}
+		w, h := ly.size()
+		if ly.x < minX {
+			minX = ly.x
+		}
+		if ly.y < minY {
+			minY = ly.y
+		}
+		if ly.x+w-1 > maxX {
+			maxX = ly.x + w - 1
+		}
+		if ly.y+h-1 > maxY {
+			maxY = ly.y + h - 1
+		}
+	}
+	frameW, frameH := maxX-minX+1, maxY-minY+1
+	if c.width > 0 {
+		frameW = c.width
+	}
+	if c.height > 0 {
+		frameH = c.height
+	}
+
+	for i := range out {
+		a := out[i].anchor
+		if a == nil {
+			continue
+		}
+		w, h := out[i].size()
+		x, y := minX+alignOffset(a.h, frameW-w), minY+alignOffset(a.v, frameH-h)
+		if a.ref != "" {
+			if j := lastNamed(out[:i], a.ref); j >= 0 {
+				rw, rh := out[j].size()
+				x, y = out[j].x, out[j].y
+				switch a.side {
+				case sideRight:
+					x += rw + a.gap
+				case sideLeft:
+					x -= w + a.gap
+				case sideBelow:
+					y += rh + a.gap
+				case sideAbove:
+					y -= h + a.gap
+				}
+			} else {
+				x, y = minX, minY
+			}
+		}
+		out[i].x += x
+		out[i].y += y
+		out[i].anchor = nil
+	}
+	return out
+}
+
+// lastNamed returns the index of the last layer named id, or -1.
+func lastNamed(layers []layer, id string) int {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].id == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,94 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasPlace(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	bg := ".....\n.....\n....."
+
+	tests := []struct {
+		name   string
+		anchor Anchor
+		want   string
+	}{
+		{"top left", AnchorTopLeft, "x....\n.....\n....."},
+		{"top", AnchorTop, "..x..\n.....\n....."},
+		{"top right", AnchorTopRight, "....x\n.....\n....."},
+		{"left", AnchorLeft, ".....\nx....\n....."},
+		{"center", AnchorCenter, ".....\n..x..\n....."},
+		{"right", AnchorRight, ".....\n....x\n....."},
+		{"bottom left", AnchorBottomLeft, ".....\n.....\nx...."},
+		{"bottom", AnchorBottom, ".....\n.....\n..x.."},
+		{"bottom right", AnchorBottomRight, ".....\n.....\n....x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Canvas().Add(bg, 0, 0).Place("x", tt.anchor, 0, 0).String()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("offset from the anchor", func(t *testing.T) {
+		got := Canvas().Add(bg, 0, 0).Place("x", AnchorBottomRight, -1, 0).String()
+		assert.Equal(t, ".....\n.....\n...x.", got)
+	})
+
+	t.Run("fixed size is the frame", func(t *testing.T) {
+		got := Canvas().Width(5).Height(3).Place("ab", AnchorCenter, 0, 0).String()
+		assert.Equal(t, "\n ab\n", got)
+	})
+
+	t.Run("frame follows negative positions", func(t *testing.T) {
+		got := Canvas().Add("...", -2, 0).Add("...", 0, 1).Place("x", AnchorTopRight, 0, 0).String()
+		assert.Equal(t, "... x\n  ...", got)
+	})
+
+	t.Run("anchored after later absolute layers", func(t *testing.T) {
+		got := Canvas().Place("x", AnchorBottom, 0, 0).Add(bg, 0, 0).String()
+		assert.Equal(t, ".....\n.....\n.....", got)
+	})
+}
+
+func TestCanvasRelativePlacement(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	menu := Box().String("menu")
+
+	t.Run("right of", func(t *testing.T) {
+		got := Canvas().AddNamed("menu", menu, 0, 0).Place("x", RightOf("menu", 1), 0, 0).String()
+		assert.Equal(t, "┌────┐ x\n│menu│\n└────┘", got)
+	})
+
+	t.Run("below", func(t *testing.T) {
+		got := Canvas().AddNamed("menu", menu, 0, 0).Place("x", Below("menu", 0), 0, 0).String()
+		assert.Equal(t, "┌────┐\n│menu│\n└────┘\nx", got)
+	})
+
+	t.Run("left of and above", func(t *testing.T) {
+		got := Canvas().AddNamed("a", "a", 3, 2).
+			Place("ll", LeftOf("a", 0), 0, 0).
+			Place("^", Above("a", 1), 0, 0).
+			String()
+		assert.Equal(t, "   ^\n\n lla", got)
+	})
+
+	t.Run("chains follow anchored layers", func(t *testing.T) {
+		got := Canvas().Width(6).Height(2).
+			PlaceNamed("a", "a", AnchorTopRight, -1, 0).
+			Place("b", Below("a", 0), 0, 0).
+			String()
+		assert.Equal(t, "    a\n    b", got)
+	})
+
+	t.Run("unknown id uses the frame origin", func(t *testing.T) {
+		got := Canvas().Add("...", 0, 0).Place("x", RightOf("nope", 1), 1, 0).String()
+		assert.Equal(t, ".x.", got)
+	})
+}
@@ -0,0 +1,184 @@
+package tinta
+
+import "math"
+
+// BlendMode selects how a translucent canvas layer mixes its colors with
+// the colors below it. See [CanvasStyle.AddBlend].
+type BlendMode int
+
+const (
+	BlendNormal   BlendMode = iota // the layer's color
+	BlendMultiply                  // darkens: below × layer
+	BlendScreen                    // lightens: inverse of multiplying the inverses
+	BlendDarken                    // the darker of both, per channel
+)
+
+// Colors assumed for the terminal's default foreground and background
+// when they take part in blending. They match a dark terminal theme.
+var (
+	defaultFg = rgb{229, 229, 229}
+	defaultBg = rgb{0, 0, 0}
+)
+
+type rgb struct{ r, g, b uint8 }
+
+// basicPalette holds the xterm values of the 16 basic colors.
+var basicPalette = [16]rgb{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6×6×6 color cube of the
+// 256-color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// toRGB resolves c to 24-bit color, using fallback for the terminal
+// default.
+func (c sgrColor) toRGB(fallback rgb) rgb {
+	switch c.mode {
+	case colorBasic:
+		return basicPalette[c.r&15]
+	case colorIndex:
+		switch i := int(c.r); {
+		case i < 16:
+			return basicPalette[i]
+		case i < 232:
+			i -= 16
+			return rgb{cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]}
+		default:
+			v := uint8(8 + 10*(i-232))
+			return rgb{v, v, v}
+		}
+	case colorRGB:
+		return rgb{c.r, c.g, c.b}
+	}
+	return fallback
+}
+
+func (c rgb) color() sgrColor {
+	return sgrColor{mode: colorRGB, r: c.r, g: c.g, b: c.b}
+}
+
+// blendChannel combines one channel of the color below with the layer's.
+func blendChannel(mode BlendMode, below, above uint8) uint8 {
+	b, a := int(below), int(above)
+	switch mode {
+	case BlendMultiply:
+		return uint8(b * a / 255)
+	case BlendScreen:
+		return uint8(255 - (255-b)*(255-a)/255)
+	case BlendDarken:
+		if b < a {
+			return below
+		}
+	}
+	return above
+}
+
+// blendRGB blends above onto below with mode, then mixes the result with
+// below by alpha.
+func blendRGB(mode BlendMode, below, above rgb, alpha float64) rgb {
+	mix := func(b, a uint8) uint8 {
+		v := blendChannel(mode, b, a)
+		return uint8(float64(b) + (float64(v)-float64(b))*alpha + 0.5)
+	}
+	return rgb{mix(below.r, above.r), mix(below.g, above.g), mix(below.b, above.b)}
+}
+
+// blendColor blends above onto below like blendRGB, with the defaults
+// used for unset colors, into a color used where out is the default. A
+// result equal to either input keeps that input as is, so colors that end
+// up unmixed stay basic or indexed colors.
+func blendColor(mode BlendMode, below, above sgrColor, belowDefault, aboveDefault, out rgb, alpha float64) sgrColor {
+	b, a := below.toRGB(belowDefault), above.toRGB(aboveDefault)
+	mixed := blendRGB(mode, b, a, alpha)
+	switch {
+	case mixed == b && (below.mode != colorNone || belowDefault == out):
+		return below
+	case mixed == a && (above.mode != colorNone || aboveDefault == out):
+		return above
+	}
+	return mixed.color()
+}
+
+// glyphAlpha is the opacity from which the glyphs of a translucent layer
+// replace the glyphs below; under it only their colors are mixed in.
+const glyphAlpha = 0.5
+
+// AddOpacity places a rendered string like [CanvasStyle.Add], drawn with
+// the given opacity between 0 and 1. It is shorthand for
+// [CanvasStyle.AddBlend] with [BlendNormal].
+func (c *CanvasStyle) AddOpacity(s string, x, y int, alpha float64) *CanvasStyle {
+	return c.AddBlend(s, x, y, BlendNormal, alpha)
+}
+
+// AddBlend places a rendered string as a translucent layer whose colors
+// are mixed with the cells below using mode, then faded by alpha between
+// 0 (invisible) and 1 (full effect). Values out of range are clamped and
+// NaN counts as 0.
+//
+// Spaces in the layer tint the cell below without hiding it: its glyph
+// stays and both its colors are blended with the space's background,
+// which makes backdrops, dimmed panels and shadows. From an alpha of 0.5,
+// other glyphs replace the glyph below and their foreground is blended
+// against the background below, so text fades into it; under 0.5 the
+// glyph below stays and only the colors are blended. Unset colors take
+// part as the terminal defaults, assumed to be light text on black.
+func (c *CanvasStyle) AddBlend(s string, x, y int, mode BlendMode, alpha float64) *CanvasStyle {
+	if alpha < 0 || math.IsNaN(alpha) {
+		alpha = 0
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+	return c.addLayer(layer{
+		grid:    c.parse(s),
+		x:       x,
+		y:       y,
+		z:       c.nextZ,
+		blended: true,
+		blend:   mode,
+		alpha:   alpha,
+	})
+}
+
+// blendCell returns the cell below after drawing the translucent cell
+// above over it. Without colors, spaces are see-through and other glyphs
+// are opaque from an alpha of glyphAlpha.
+func blendCell(below, above cell, mode BlendMode, alpha float64) cell {
+	if alpha == 0 {
+		return below
+	}
+	if !isEnabled() {
+		if above.r == ' ' || alpha < glyphAlpha {
+			return below
+		}
+		return above
+	}
+	if above.r == ' ' {
+		if above.style.bg.mode == colorNone {
+			return below
+		}
+		below.style.fg = blendColor(mode, below.style.fg, above.style.bg, defaultFg, defaultBg, defaultFg, alpha)
+		below.style.bg = blendColor(mode, below.style.bg, above.style.bg, defaultBg, defaultBg, defaultBg, alpha)
+		below.clear = false
+		return below
+	}
+
+	out := above
+	if alpha < glyphAlpha {
+		out = below
+		out.style.fg = blendColor(mode, below.style.fg, above.style.fg, defaultFg, defaultFg, defaultFg, alpha)
+	} else {
+		out.style.fg = blendColor(mode, below.style.bg, above.style.fg, defaultBg, defaultFg, defaultFg, alpha)
+	}
+	if above.style.bg.mode == colorNone {
+		out.style.bg = below.style.bg
+	} else {
+		out.style.bg = blendColor(mode, below.style.bg, above.style.bg, defaultBg, defaultBg, defaultBg, alpha)
+	}
+	out.clear = false
+	return out
+}
@@ -0,0 +1,107 @@
+package tinta
+
+import (
+	"math"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestColorToRGB(t *testing.T) {
+	assert.Equal(t, rgb{205, 0, 0}, sgrColor{mode: colorBasic, r: 1}.toRGB(defaultFg))
+	assert.Equal(t, rgb{255, 255, 255}, sgrColor{mode: colorBasic, r: 15}.toRGB(defaultFg))
+	assert.Equal(t, rgb{255, 135, 0}, sgrColor{mode: colorIndex, r: 208}.toRGB(defaultFg))
+	assert.Equal(t, rgb{8, 8, 8}, sgrColor{mode: colorIndex, r: 232}.toRGB(defaultFg))
+	assert.Equal(t, rgb{1, 2, 3}, sgrColor{mode: colorRGB, r: 1, g: 2, b: 3}.toRGB(defaultFg))
+	assert.Equal(t, defaultBg, sgrColor{}.toRGB(defaultBg))
+}
+
+func TestBlendRGB(t *testing.T) {
+	below := rgb{200, 100, 0}
+	above := rgb{100, 200, 255}
+
+	tests := []struct {
+		name  string
+		mode  BlendMode
+		alpha float64
+		want  rgb
+	}{
+		{"normal", BlendNormal, 1, above},
+		{"normal half", BlendNormal, 0.5, rgb{150, 150, 128}},
+		{"invisible", BlendNormal, 0, below},
+		{"multiply", BlendMultiply, 1, rgb{78, 78, 0}},
+		{"screen", BlendScreen, 1, rgb{222, 222, 255}},
+		{"darken", BlendDarken, 1, rgb{100, 100, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, blendRGB(tt.mode, below, above, tt.alpha))
+		})
+	}
+}
+
+func TestCanvasBlend(t *testing.T) {
+	ForceColors(true)
+
+	t.Run("backdrop tints without hiding", func(t *testing.T) {
+		below := Text().White().OnBlue().String("ab")
+		got := Canvas().Add(below, 0, 0).AddOpacity(Text().OnBlack().String(" "), 0, 0, 0.5).String()
+		assert.Equal(t, "\x1b[38;2;115;115;115;48;2;0;0;119ma\x1b[37;44mb\x1b[0m", got)
+	})
+
+	t.Run("text fades into the background", func(t *testing.T) {
+		got := Canvas().Add(Text().OnBlue().String(" "), 0, 0).AddOpacity(Text().Red().String("x"), 0, 0, 0.5).String()
+		assert.Equal(t, "\x1b[38;2;103;0;119;44mx\x1b[0m", got)
+	})
+
+	t.Run("space without background leaves the cell", func(t *testing.T) {
+		got := Canvas().Add("ab", 0, 0).AddOpacity("  ", 0, 0, 0.5).String()
+		assert.Equal(t, "ab", got)
+	})
+
+	t.Run("multiply darkens", func(t *testing.T) {
+		below := Text().OnWhite().String(" ")
+		got := Canvas().Add(below, 0, 0).AddBlend(Text().OnRed().String(" "), 0, 0, BlendMultiply, 1).String()
+		assert.Equal(t, "\x1b[38;2;184;0;0;48;2;184;0;0m \x1b[0m", got)
+	})
+
+	t.Run("alpha is clamped", func(t *testing.T) {
+		got := Canvas().Add("a", 0, 0).AddOpacity(Text().OnRed().String(" "), 0, 0, 2).String()
+		assert.Equal(t, "\x1b[31;41ma\x1b[0m", got)
+
+		got = Canvas().Add("abc", 0, 0).AddOpacity("xyz", 0, 0, -1).String()
+		assert.Equal(t, "abc", got)
+	})
+
+	t.Run("alpha 0 is invisible", func(t *testing.T) {
+		below := Text().Red().OnBlue().String("abc")
+		got := Canvas().Add(below, 0, 0).AddOpacity(Text().Green().String("xyz"), 0, 0, 0).String()
+		assert.Equal(t, below, got)
+		assert.Equal(t, "abc", Canvas().Add("abc", 0, 0).AddOpacity("xyz", 0, 0, 0).String())
+	})
+
+	t.Run("NaN is invisible", func(t *testing.T) {
+		got := Canvas().Add("abc", 0, 0).AddOpacity("xyz", 0, 0, math.NaN()).String()
+		assert.Equal(t, "abc", got)
+	})
+
+	t.Run("alpha 1 keeps unmixed colors", func(t *testing.T) {
+		below := Text().OnBlue().String("abc")
+		got := Canvas().Add(below, 0, 0).AddOpacity(Text().Red().String("xyz"), 0, 0, 1).String()
+		assert.Equal(t, "\x1b[31;44mxyz\x1b[0m", got)
+		assert.Equal(t, "xyz", Canvas().Add("abc", 0, 0).AddOpacity("xyz", 0, 0, 1).String())
+	})
+
+	t.Run("low alpha keeps the glyph below", func(t *testing.T) {
+		got := Canvas().Add(Text().Blue().String("a"), 0, 0).AddOpacity(Text().Red().String("x"), 0, 0, 0.25).String()
+		assert.Equal(t, "\x1b[38;2;51;0;179ma\x1b[0m", got)
+	})
+
+	t.Run("without colors", func(t *testing.T) {
+		ForceColors(false)
+		defer ForceColors(true)
+		got := Canvas().Add("abc", 0, 0).AddOpacity("x y", 0, 0, 0.5).String()
+		assert.Equal(t, "xby", got)
+		assert.Equal(t, "abc", Canvas().Add("abc", 0, 0).AddOpacity("x y", 0, 0, 0.25).String())
+	})
+}
@@ -6,20 +6,32 @@ import (
 	"strings"
 )
 
-// Border defines the glyphs used to draw a box frame.
+// Border defines the glyphs used to draw a box frame. The Middle*
+// junctions are where an inner line meets the outer frame, Cross is where
+// two inner lines cross, and InnerHorizontal and InnerVertical draw the
+// inner lines themselves. Tables, grids and box dividers use them; an
+// empty junction field falls back to a glyph matching the frame.
 type Border struct {
-	TopLeft     string
-	TopRight    string
-	BottomRight string
-	BottomLeft  string
-	Top         string
-	Right       string
-	Bottom      string
-	Left        string
+	TopLeft         string
+	TopRight        string
+	BottomRight     string
+	BottomLeft      string
+	Top             string
+	Right           string
+	Bottom          string
+	Left            string
+	MiddleLeft      string
+	MiddleRight     string
+	MiddleTop       string
+	MiddleBottom    string
+	Cross           string
+	InnerHorizontal string
+	InnerVertical   string
 }
 
 // Align controls the horizontal alignment of title and footer text
-// within a box border.
+// within a box border. Layout helpers such as [JoinHorizontal] also use it
+// for vertical alignment through [AlignTop], [AlignMiddle] and [AlignBottom].
 type Align int
 
 const (
@@ -31,150 +43,431 @@ const (
 	AlignRight
 )
 
+// Vertical alignments. They share their values with the horizontal ones:
+// top behaves like left, middle like center and bottom like right.
+const (
+	// AlignTop places content at the top edge.
+	AlignTop = AlignLeft
+	// AlignMiddle places content at the vertical center.
+	AlignMiddle = AlignCenter
+	// AlignBottom places content at the bottom edge.
+	AlignBottom = AlignRight
+)
+
 // Predefined border styles.
 var (
 	BorderSimple = Border{
-		TopLeft:     "┌",
-		TopRight:    "┐",
-		BottomLeft:  "└",
-		BottomRight: "┘",
-		Top:         "─",
-		Left:        "│",
-		Right:       "│",
-		Bottom:      "─",
+		TopLeft:         "┌",
+		TopRight:        "┐",
+		BottomLeft:      "└",
+		BottomRight:     "┘",
+		Top:             "─",
+		Left:            "│",
+		Right:           "│",
+		Bottom:          "─",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "─",
+		InnerVertical:   "│",
 	}
 	BorderDashed = Border{
-		TopLeft:     "┌",
-		TopRight:    "┐",
-		BottomLeft:  "└",
-		BottomRight: "┘",
-		Top:         "╌",
-		Left:        "╎",
-		Right:       "╎",
-		Bottom:      "╌",
+		TopLeft:         "┌",
+		TopRight:        "┐",
+		BottomLeft:      "└",
+		BottomRight:     "┘",
+		Top:             "╌",
+		Left:            "╎",
+		Right:           "╎",
+		Bottom:          "╌",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "╌",
+		InnerVertical:   "╎",
 	}
 	BorderDotted = Border{
-		TopLeft:     "┌",
-		TopRight:    "┐",
-		BottomLeft:  "└",
-		BottomRight: "┘",
-		Top:         "┈",
-		Left:        "┊",
-		Right:       "┊",
-		Bottom:      "┈",
+		TopLeft:         "┌",
+		TopRight:        "┐",
+		BottomLeft:      "└",
+		BottomRight:     "┘",
+		Top:             "┈",
+		Left:            "┊",
+		Right:           "┊",
+		Bottom:          "┈",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "┈",
+		InnerVertical:   "┊",
 	}
 	BorderRounded = Border{
-		TopLeft:     "╭",
-		TopRight:    "╮",
-		BottomLeft:  "╰",
-		BottomRight: "╯",
-		Top:         "─",
-		Left:        "│",
-		Right:       "│",
-		Bottom:      "─",
+		TopLeft:         "╭",
+		TopRight:        "╮",
+		BottomLeft:      "╰",
+		BottomRight:     "╯",
+		Top:             "─",
+		Left:            "│",
+		Right:           "│",
+		Bottom:          "─",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "─",
+		InnerVertical:   "│",
 	}
 	BorderRoundedDashed = Border{
-		TopLeft:     "╭",
-		TopRight:    "╮",
-		BottomLeft:  "╰",
-		BottomRight: "╯",
-		Top:         "╌",
-		Left:        "╎",
-		Right:       "╎",
-		Bottom:      "╌",
+		TopLeft:         "╭",
+		TopRight:        "╮",
+		BottomLeft:      "╰",
+		BottomRight:     "╯",
+		Top:             "╌",
+		Left:            "╎",
+		Right:           "╎",
+		Bottom:          "╌",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "╌",
+		InnerVertical:   "╎",
 	}
 	BorderRoundedDotted = Border{
-		TopLeft:     "╭",
-		TopRight:    "╮",
-		BottomLeft:  "╰",
-		BottomRight: "╯",
-		Top:         "┈",
-		Left:        "┊",
-		Right:       "┊",
-		Bottom:      "┈",
+		TopLeft:         "╭",
+		TopRight:        "╮",
+		BottomLeft:      "╰",
+		BottomRight:     "╯",
+		Top:             "┈",
+		Left:            "┊",
+		Right:           "┊",
+		Bottom:          "┈",
+		MiddleLeft:      "├",
+		MiddleRight:     "┤",
+		MiddleTop:       "┬",
+		MiddleBottom:    "┴",
+		Cross:           "┼",
+		InnerHorizontal: "┈",
+		InnerVertical:   "┊",
 	}
 	BorderDouble = Border{
-		TopLeft:     "╔",
-		TopRight:    "╗",
-		BottomLeft:  "╚",
-		BottomRight: "╝",
-		Top:         "═",
-		Left:        "║",
-		Right:       "║",
-		Bottom:      "═",
+		TopLeft:         "╔",
+		TopRight:        "╗",
+		BottomLeft:      "╚",
+		BottomRight:     "╝",
+		Top:             "═",
+		Left:            "║",
+		Right:           "║",
+		Bottom:          "═",
+		MiddleLeft:      "╠",
+		MiddleRight:     "╣",
+		MiddleTop:       "╦",
+		MiddleBottom:    "╩",
+		Cross:           "╬",
+		InnerHorizontal: "═",
+		InnerVertical:   "║",
 	}
 	BorderHeavy = Border{
-		TopLeft:     "┏",
-		TopRight:    "┓",
-		BottomLeft:  "┗",
-		BottomRight: "┛",
-		Top:         "━",
-		Left:        "┃",
-		Right:       "┃",
-		Bottom:      "━",
+		TopLeft:         "┏",
+		TopRight:        "┓",
+		BottomLeft:      "┗",
+		BottomRight:     "┛",
+		Top:             "━",
+		Left:            "┃",
+		Right:           "┃",
+		Bottom:          "━",
+		MiddleLeft:      "┣",
+		MiddleRight:     "┫",
+		MiddleTop:       "┳",
+		MiddleBottom:    "┻",
+		Cross:           "╋",
+		InnerHorizontal: "━",
+		InnerVertical:   "┃",
+	}
+	BorderDoubleSingle = Border{
+		TopLeft:         "╔",
+		TopRight:        "╗",
+		BottomLeft:      "╚",
+		BottomRight:     "╝",
+		Top:             "═",
+		Left:            "║",
+		Right:           "║",
+		Bottom:          "═",
+		MiddleLeft:      "╟",
+		MiddleRight:     "╢",
+		MiddleTop:       "╤",
+		MiddleBottom:    "╧",
+		Cross:           "┼",
+		InnerHorizontal: "─",
+		InnerVertical:   "│",
+	}
+	BorderHeavySingle = Border{
+		TopLeft:         "┏",
+		TopRight:        "┓",
+		BottomLeft:      "┗",
+		BottomRight:     "┛",
+		Top:             "━",
+		Left:            "┃",
+		Right:           "┃",
+		Bottom:          "━",
+		MiddleLeft:      "┠",
+		MiddleRight:     "┨",
+		MiddleTop:       "┯",
+		MiddleBottom:    "┷",
+		Cross:           "┼",
+		InnerHorizontal: "─",
+		InnerVertical:   "│",
 	}
 	BorderASCII = Border{
-		TopLeft:     "+",
-		TopRight:    "+",
-		BottomLeft:  "+",
-		BottomRight: "+",
-		Top:         "-",
-		Left:        "|",
-		Right:       "|",
-		Bottom:      "-",
+		TopLeft:         "+",
+		TopRight:        "+",
+		BottomLeft:      "+",
+		BottomRight:     "+",
+		Top:             "-",
+		Left:            "|",
+		Right:           "|",
+		Bottom:          "-",
+		MiddleLeft:      "+",
+		MiddleRight:     "+",
+		MiddleTop:       "+",
+		MiddleBottom:    "+",
+		Cross:           "+",
+		InnerHorizontal: "-",
+		InnerVertical:   "|",
 	}
 	BorderBlock = Border{
-		TopLeft:     "█",
-		TopRight:    "█",
-		BottomLeft:  "█",
-		BottomRight: "█",
-		Top:         "█",
-		Left:        "█",
-		Right:       "█",
-		Bottom:      "█",
+		TopLeft:         "█",
+		TopRight:        "█",
+		BottomLeft:      "█",
+		BottomRight:     "█",
+		Top:             "█",
+		Left:            "█",
+		Right:           "█",
+		Bottom:          "█",
+		MiddleLeft:      "█",
+		MiddleRight:     "█",
+		MiddleTop:       "█",
+		MiddleBottom:    "█",
+		Cross:           "█",
+		InnerHorizontal: "█",
+		InnerVertical:   "█",
 	}
 	BorderBlockHalf = Border{
-		TopLeft:     "▀",
-		TopRight:    "▀",
-		BottomLeft:  "▄",
-		BottomRight: "▄",
-		Top:         "▀",
-		Left:        "▄",
-		Right:       "▄",
-		Bottom:      "▄",
+		TopLeft:         "▀",
+		TopRight:        "▀",
+		BottomLeft:      "▄",
+		BottomRight:     "▄",
+		Top:             "▀",
+		Left:            "▄",
+		Right:           "▄",
+		Bottom:          "▄",
+		MiddleLeft:      "█",
+		MiddleRight:     "█",
+		MiddleTop:       "█",
+		MiddleBottom:    "█",
+		Cross:           "█",
+		InnerHorizontal: "▀",
+		InnerVertical:   "▄",
 	}
 	BorderBlockLight = Border{
-		TopLeft:     "░",
-		TopRight:    "░",
-		BottomLeft:  "░",
-		BottomRight: "░",
-		Top:         "░",
-		Left:        "░",
-		Right:       "░",
-		Bottom:      "░",
+		TopLeft:         "░",
+		TopRight:        "░",
+		BottomLeft:      "░",
+		BottomRight:     "░",
+		Top:             "░",
+		Left:            "░",
+		Right:           "░",
+		Bottom:          "░",
+		MiddleLeft:      "░",
+		MiddleRight:     "░",
+		MiddleTop:       "░",
+		MiddleBottom:    "░",
+		Cross:           "░",
+		InnerHorizontal: "░",
+		InnerVertical:   "░",
 	}
 	BorderBlockMedium = Border{
-		TopLeft:     "▒",
-		TopRight:    "▒",
-		BottomLeft:  "▒",
-		BottomRight: "▒",
-		Top:         "▒",
-		Left:        "▒",
-		Right:       "▒",
-		Bottom:      "▒",
+		TopLeft:         "▒",
+		TopRight:        "▒",
+		BottomLeft:      "▒",
+		BottomRight:     "▒",
+		Top:             "▒",
+		Left:            "▒",
+		Right:           "▒",
+		Bottom:          "▒",
+		MiddleLeft:      "▒",
+		MiddleRight:     "▒",
+		MiddleTop:       "▒",
+		MiddleBottom:    "▒",
+		Cross:           "▒",
+		InnerHorizontal: "▒",
+		InnerVertical:   "▒",
 	}
 	BorderBlockDark = Border{
-		TopLeft:     "▓",
-		TopRight:    "▓",
-		BottomLeft:  "▓",
-		BottomRight: "▓",
-		Top:         "▓",
-		Left:        "▓",
-		Right:       "▓",
-		Bottom:      "▓",
+		TopLeft:         "▓",
+		TopRight:        "▓",
+		BottomLeft:      "▓",
+		BottomRight:     "▓",
+		Top:             "▓",
+		Left:            "▓",
+		Right:           "▓",
+		Bottom:          "▓",
+		MiddleLeft:      "▓",
+		MiddleRight:     "▓",
+		MiddleTop:       "▓",
+		MiddleBottom:    "▓",
+		Cross:           "▓",
+		InnerHorizontal: "▓",
+		InnerVertical:   "▓",
 	}
 )
 
+// edges is a set of directions, used to describe which lines leave a
+// junction point and which sides of the outer frame it lies on.
+type edges uint8
+
+const (
+	edgeUp edges = 1 << iota
+	edgeDown
+	edgeLeft
+	edgeRight
+)
+
+// edgeSet builds an [edges] value from one flag per direction.
+func edgeSet(up, down, left, right bool) edges {
+	var e edges
+	if up {
+		e |= edgeUp
+	}
+	if down {
+		e |= edgeDown
+	}
+	if left {
+		e |= edgeLeft
+	}
+	if right {
+		e |= edgeRight
+	}
+	return e
+}
+
+// tees returns the T and cross glyphs that match the weight of a vertical
+// line glyph. Glyphs that are not box-drawing lines get fallback instead,
+// which suits ASCII and block borders.
+func tees(vertical, fallback string) (left, right, top, bottom, cross string) {
+	switch vertical {
+	case "│", "╎", "┊":
+		return "├", "┤", "┬", "┴", "┼"
+	case "┃":
+		return "┣", "┫", "┳", "┻", "╋"
+	case "║":
+		return "╠", "╣", "╦", "╩", "╬"
+	}
+	return fallback, fallback, fallback, fallback, fallback
+}
+
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func (b Border) innerHorizontal() string {
+	return orDefault(b.InnerHorizontal, b.Top)
+}
+
+func (b Border) innerVertical() string {
+	return orDefault(b.InnerVertical, b.Left)
+}
+
+func (b Border) middleLeft() string {
+	ml, _, _, _, _ := tees(b.Left, b.TopLeft)
+	return orDefault(b.MiddleLeft, ml)
+}
+
+func (b Border) middleRight() string {
+	_, mr, _, _, _ := tees(b.Right, b.TopRight)
+	return orDefault(b.MiddleRight, mr)
+}
+
+// junction returns the glyph for a point where lines leave in the given
+// directions, or "" when no line touches it. frame holds the sides of the
+// outer frame the point lies on, which decides between outer glyphs,
+// Middle* junctions and inner glyphs.
+func (b Border) junction(lines, frame edges) string {
+	if lines == 0 {
+		return ""
+	}
+	ml, mr, mt, mb, _ := tees(b.Left, b.TopLeft)
+	il, ir, it, ib, cross := tees(b.innerVertical(), orDefault(b.Cross, b.TopLeft))
+	cross = orDefault(b.Cross, cross)
+
+	switch {
+	case frame&(edgeUp|edgeLeft) == edgeUp|edgeLeft:
+		return b.TopLeft
+	case frame&(edgeUp|edgeRight) == edgeUp|edgeRight:
+		return b.TopRight
+	case frame&(edgeDown|edgeLeft) == edgeDown|edgeLeft:
+		return b.BottomLeft
+	case frame&(edgeDown|edgeRight) == edgeDown|edgeRight:
+		return b.BottomRight
+	case frame&edgeUp != 0:
+		if lines&edgeDown != 0 {
+			return orDefault(b.MiddleTop, mt)
+		}
+		return b.Top
+	case frame&edgeDown != 0:
+		if lines&edgeUp != 0 {
+			return orDefault(b.MiddleBottom, mb)
+		}
+		return b.Bottom
+	case frame&edgeLeft != 0:
+		if lines&edgeRight != 0 {
+			return orDefault(b.MiddleLeft, ml)
+		}
+		return b.Left
+	case frame&edgeRight != 0:
+		if lines&edgeLeft != 0 {
+			return orDefault(b.MiddleRight, mr)
+		}
+		return b.Right
+	}
+
+	switch lines {
+	case edgeUp | edgeDown | edgeLeft | edgeRight:
+		return cross
+	case edgeUp | edgeDown | edgeRight:
+		return il
+	case edgeUp | edgeDown | edgeLeft:
+		return ir
+	case edgeLeft | edgeRight | edgeDown:
+		return it
+	case edgeLeft | edgeRight | edgeUp:
+		return ib
+	case edgeDown | edgeRight:
+		return b.TopLeft
+	case edgeDown | edgeLeft:
+		return b.TopRight
+	case edgeUp | edgeRight:
+		return b.BottomLeft
+	case edgeUp | edgeLeft:
+		return b.BottomRight
+	}
+	if lines&(edgeUp|edgeDown) != 0 {
+		return b.innerVertical()
+	}
+	return b.innerHorizontal()
+}
+
 // BoxStyle holds the configuration for a bordered terminal container.
 // Create one with [Box] and chain border, padding, margin, alignment,
 // corner controls, and color methods. All fields are unexported to
@@ -207,6 +500,28 @@ type BoxStyle struct {
 	titleAlign   Align
 	footer       string
 	footerAlign  Align
+	tabWidth     int
+	width        int
+	widthPercent int
+	minHeight    int
+	shadow       *ShadowStyle
+	shadowX      int
+	shadowY      int
+	sections     []boxSection
+}
+
+// boxSection is a part of the content added with [BoxStyle.Section].
+type boxSection struct {
+	label   string
+	align   Align
+	content string
+}
+
+// boxLine is a line of box content, or a divider whose label is text.
+type boxLine struct {
+	text    string
+	divider bool
+	align   Align
 }
 
 // Box returns a new [BoxStyle] with a simple border and no padding or margin.
@@ -226,6 +541,10 @@ func copyBox(b *BoxStyle) *BoxStyle {
 			cp.centerLines[k] = v
 		}
 	}
+	if len(b.sections) > 0 {
+		cp.sections = make([]boxSection, len(b.sections))
+		copy(cp.sections, b.sections)
+	}
 	return &cp
 }
 
@@ -350,6 +669,44 @@ func (b *BoxStyle) MarginY(n int) *BoxStyle {
 	return cp
 }
 
+// TabWidth sets the tab stop interval used to expand tab characters in the
+// content before it is measured. Values below 1 restore the default of 8.
+func (b *BoxStyle) TabWidth(n int) *BoxStyle {
+	cp := copyBox(b)
+	cp.tabWidth = n
+	return cp
+}
+
+// Width sets the total rendered width of the box, margins included.
+// Content lines that do not fit the remaining inner width are word-wrapped,
+// breaking words that are longer than a whole line. A title or footer wider
+// than the box still widens it. Zero restores automatic sizing.
+func (b *BoxStyle) Width(n int) *BoxStyle {
+	cp := copyBox(b)
+	cp.width = n
+	cp.widthPercent = 0
+	return cp
+}
+
+// FullWidth stretches the box so that, including margins, it spans the
+// full width of the terminal. It is shorthand for WidthPercent(100).
+func (b *BoxStyle) FullWidth() *BoxStyle {
+	return b.WidthPercent(100)
+}
+
+// WidthPercent stretches the box so that, including margins, it spans
+// pct percent of the terminal width. The terminal is measured with
+// [TerminalSize] at render time, using the destination writer for the
+// Fprint family and the default output otherwise. Content is wrapped to
+// the resolved width the way [BoxStyle.Width] wraps it. A value of zero or
+// less disables stretching.
+func (b *BoxStyle) WidthPercent(pct int) *BoxStyle {
+	cp := copyBox(b)
+	cp.widthPercent = pct
+	cp.width = 0
+	return cp
+}
+
 // Center enables horizontal centering of content lines within the box.
 // Shorter lines are padded equally on both sides to match the widest line.
 func (b *BoxStyle) Center() *BoxStyle {
@@ -486,6 +843,46 @@ func (b *BoxStyle) Footer(text string, align Align) *BoxStyle {
 	return cp
 }
 
+// Shadow adds a drop shadow offset by (dx, dy) cells, drawn with style;
+// see [ShadowStyle]. The shadow is part of the rendered string, which
+// grows by |dx| columns and |dy| rows; margins are added around both. With
+// [BoxStyle.FullWidth] or [BoxStyle.WidthPercent] the shadow's columns
+// count toward the resolved width instead.
+func (b *BoxStyle) Shadow(dx, dy int, style ShadowStyle) *BoxStyle {
+	cp := copyBox(b)
+	cp.shadow = &style
+	cp.shadowX = dx
+	cp.shadowY = dy
+	return cp
+}
+
+// Section adds a part of content drawn below the box content, after a
+// horizontal rule across the box interior drawn with the border's junction
+// and inner line glyphs. A non-empty label is placed inside the rule the
+// way [BoxStyle.Title] places a title. Sections are drawn in the order
+// they are added.
+func (b *BoxStyle) Section(label string, align Align, content string) *BoxStyle {
+	cp := copyBox(b)
+	cp.sections = append(cp.sections, boxSection{label: label, align: align, content: content})
+	return cp
+}
+
+// Sections renders parts inside the box, separated by unlabeled dividers.
+// It is shorthand for adding all parts but the first with
+// [BoxStyle.Section] and rendering the first with [BoxStyle.String]; use
+// Section directly for labeled dividers or to render with the Fprint
+// family.
+func (b *BoxStyle) Sections(parts ...string) string {
+	if len(parts) == 0 {
+		return b.String("")
+	}
+	cp := b
+	for _, part := range parts[1:] {
+		cp = cp.Section("", AlignLeft, part)
+	}
+	return cp.String(parts[0])
+}
+
 func (b *BoxStyle) OnBlack() *BoxStyle   { return b.withCode(cOnBlack) }
 func (b *BoxStyle) OnRed() *BoxStyle     { return b.withCode(cOnRed) }
 func (b *BoxStyle) OnGreen() *BoxStyle   { return b.withCode(cOnGreen) }
@@ -527,42 +924,45 @@ func (b *BoxStyle) Dim() *BoxStyle  { return b.withCode(cDim) }
 
 // String renders the box around the given content and returns the result.
 func (b *BoxStyle) String(content string) string {
-	return b.render(content)
+	return b.render(getOutput(), content)
 }
 
 // Sprintf formats the content, renders it inside the box, and returns the result.
 func (b *BoxStyle) Sprintf(format string, a ...any) string {
-	return b.render(fmt.Sprintf(format, a...))
+	return b.render(getOutput(), fmt.Sprintf(format, a...))
 }
 
 // Print renders the box and writes it to the default output.
 func (b *BoxStyle) Print(content string) {
-	_, _ = fmt.Fprint(getOutput(), b.render(content))
+	w := getOutput()
+	_, _ = fmt.Fprint(w, b.render(w, content))
 }
 
 // Printf formats the content, renders it inside the box, and writes to the default output.
 func (b *BoxStyle) Printf(format string, a ...any) {
-	_, _ = fmt.Fprint(getOutput(), b.render(fmt.Sprintf(format, a...)))
+	w := getOutput()
+	_, _ = fmt.Fprint(w, b.render(w, fmt.Sprintf(format, a...)))
 }
 
 // Println renders the box and writes it followed by a newline to the default output.
 func (b *BoxStyle) Println(content string) {
-	_, _ = fmt.Fprintln(getOutput(), b.render(content))
+	w := getOutput()
+	_, _ = fmt.Fprintln(w, b.render(w, content))
 }
 
 // Fprint renders the box and writes it to w.
 func (b *BoxStyle) Fprint(w io.Writer, content string) (int, error) {
-	return fmt.Fprint(w, b.render(content))
+	return fmt.Fprint(w, b.render(w, content))
 }
 
 // Fprintf formats the content, renders it inside the box, and writes to w.
 func (b *BoxStyle) Fprintf(w io.Writer, format string, a ...any) (int, error) {
-	return fmt.Fprint(w, b.render(fmt.Sprintf(format, a...)))
+	return fmt.Fprint(w, b.render(w, fmt.Sprintf(format, a...)))
 }
 
 // Fprintln renders the box and writes it followed by a newline to w.
 func (b *BoxStyle) Fprintln(w io.Writer, content string) (int, error) {
-	return fmt.Fprintln(w, b.render(content))
+	return fmt.Fprintln(w, b.render(w, content))
 }
 
 func wrapCodes(s string, codes []string) string {
@@ -651,24 +1051,77 @@ func (b *BoxStyle) buildBorderRow(cornerLeft, cornerRight, edge string, hideLeft
 		cr
 }
 
-func (b *BoxStyle) render(content string) string {
-	lines := strings.Split(content, "\n")
+func (b *BoxStyle) render(w io.Writer, content string) string {
+	var lines []boxLine
+	addLines := func(content string) {
+		for _, line := range strings.Split(expandTabs(content, b.tabWidth), "\n") {
+			if b.centerTrim {
+				line = strings.TrimSpace(line)
+			}
+			lines = append(lines, boxLine{text: line})
+		}
+	}
+	addLines(content)
+	for _, s := range b.sections {
+		lines = append(lines, boxLine{text: s.label, divider: true, align: s.align})
+		addLines(s.content)
+	}
+
+	leftW := visibleWidth(b.border.Left)
+	rightW := visibleWidth(b.border.Right)
+	leftSum := leftW + rightW
+
+	width := b.width
+	if b.widthPercent > 0 {
+		cols, _ := TerminalSize(w)
+		width = cols * b.widthPercent / 100
+		// The shadow is drawn outside the box, so it takes its columns
+		// from the terminal width too.
+		if b.shadow != nil {
+			if b.shadowX < 0 {
+				width += b.shadowX
+			} else {
+				width -= b.shadowX
+			}
+		}
+	}
 
-	if b.centerTrim {
-		for i, line := range lines {
-			lines[i] = strings.TrimSpace(line)
+	if width > 0 {
+		availW := width - b.marginLeft - b.marginRight - leftSum - b.padLeft - b.padRight
+		if availW < 1 {
+			availW = 1
+		}
+		wrapped := make([]boxLine, 0, len(lines))
+		for _, line := range lines {
+			if line.divider {
+				wrapped = append(wrapped, line)
+				continue
+			}
+			for _, text := range wrapLine(line.text, availW) {
+				wrapped = append(wrapped, boxLine{text: text})
+			}
 		}
+		lines = wrapped
 	}
 
 	maxW := 0
 	for _, line := range lines {
-		w := visibleWidth(line)
-		if w > maxW {
-			maxW = w
+		if line.divider {
+			continue
+		}
+		lw := visibleWidth(line.text)
+		if lw > maxW {
+			maxW = lw
 		}
 	}
 
 	innerW := maxW + b.padLeft + b.padRight
+	if width > 0 {
+		target := width - b.marginLeft - b.marginRight - leftSum
+		if target > innerW {
+			innerW = target
+		}
+	}
 
 	topHorW := visibleWidth(b.border.Top)
 	if topHorW == 0 {
@@ -678,9 +1131,6 @@ func (b *BoxStyle) render(content string) string {
 	if botHorW == 0 {
 		botHorW = 1
 	}
-	leftW := visibleWidth(b.border.Left)
-	rightW := visibleWidth(b.border.Right)
-	leftSum := leftW + rightW
 
 	clW := visibleWidth(b.border.TopLeft)
 	crW := visibleWidth(b.border.TopRight)
@@ -703,6 +1153,21 @@ func (b *BoxStyle) render(content string) string {
 		}
 	}
 
+	for _, line := range lines {
+		if !line.divider || line.text == "" {
+			continue
+		}
+		horW := visibleWidth(b.border.innerHorizontal())
+		if horW == 0 {
+			horW = 1
+		}
+		needed := visibleWidth(line.text) + 2*horW
+		edgeDelta := leftSum - visibleWidth(b.border.middleLeft()) - visibleWidth(b.border.middleRight())
+		if minInner := needed - edgeDelta; minInner > innerW {
+			innerW = minInner
+		}
+	}
+
 	leftVert := b.border.Left
 	rightVert := b.border.Right
 	if b.hideLeft {
@@ -712,6 +1177,20 @@ func (b *BoxStyle) render(content string) string {
 		rightVert = strings.Repeat(" ", rightW)
 	}
 
+	lastIdx := len(lines) - 1
+	if b.minHeight > 0 {
+		rows := b.marginTop + b.padTop + len(lines) + b.padBottom + b.marginBottom
+		if !b.hideTop {
+			rows++
+		}
+		if !b.hideBottom {
+			rows++
+		}
+		for ; rows < b.minHeight; rows++ {
+			lines = append(lines, boxLine{})
+		}
+	}
+
 	var boxRows []string
 	totalBodyRows := b.padTop + len(lines) + b.padBottom
 
@@ -762,12 +1241,20 @@ func (b *BoxStyle) render(content string) string {
 		boxRows = append(boxRows, b.wrapStyle(padLine))
 	}
 
-	lastIdx := len(lines) - 1
 	for i := 0; i < len(lines); i++ {
 		bodyIdx := b.padTop + i
 		leftGlyph, rightGlyph := bodyEdgeGlyphs(bodyIdx)
 
-		line := lines[i]
+		if lines[i].divider {
+			rule := b.buildBorderRow(
+				b.border.middleLeft(), b.border.middleRight(), b.border.innerHorizontal(),
+				b.hideLeft, b.hideRight,
+				lines[i].text, lines[i].align, frameW,
+			)
+			boxRows = append(boxRows, b.wrapStyle(rule))
+			continue
+		}
+		line := lines[i].text
 		vis := visibleWidth(line)
 		availW := innerW - b.padLeft - b.padRight
 
@@ -817,6 +1304,12 @@ func (b *BoxStyle) render(content string) string {
 		boxRows = append(boxRows, b.wrapStyle(botBar))
 	}
 
+	if b.shadow != nil {
+		block := strings.Join(boxRows, "\n")
+		block = Canvas().AddShadow(block, 0, 0, b.shadowX, b.shadowY, *b.shadow).String()
+		boxRows = strings.Split(padBlock(block), "\n")
+	}
+
 	bottomBorderIdx := -1
 	if !b.hideBottom {
 		bottomBorderIdx = len(boxRows) - 1
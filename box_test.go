@@ -1304,3 +1304,285 @@ func TestBox3DBorderEffect(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestBoxTabWidth(t *testing.T) {
+	t.Run("default tab width is 8", func(t *testing.T) {
+		got := Box().String("a\tb")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌─────────┐", lines[0])
+		assert.Equal(t, "│a       b│", lines[1])
+	})
+
+	t.Run("custom tab width aligns right border", func(t *testing.T) {
+		got := Box().TabWidth(4).String("all:\tbuild\nx\ty")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌─────────────┐", lines[0])
+		assert.Equal(t, "│all:    build│", lines[1])
+		assert.Equal(t, "│x   y        │", lines[2])
+	})
+
+	t.Run("tab stops ignore padding", func(t *testing.T) {
+		got := Box().TabWidth(4).PaddingLeft(1).String("a\tb")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│ a   b│", lines[1])
+	})
+
+	t.Run("immutability", func(t *testing.T) {
+		base := Box()
+		_ = base.TabWidth(2)
+		assert.Equal(t, 0, base.tabWidth)
+	})
+}
+
+func TestBoxWidthPercent(t *testing.T) {
+	t.Setenv("COLUMNS", "20")
+
+	t.Run("full width spans the terminal", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _ = Box().FullWidth().Fprint(&buf, "hi")
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, "┌──────────────────┐", lines[0])
+		assert.Equal(t, "│hi                │", lines[1])
+		assert.Equal(t, 20, visibleWidth(lines[0]))
+	})
+
+	t.Run("percentage width", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _ = Box().WidthPercent(50).Fprint(&buf, "hi")
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, "┌────────┐", lines[0])
+		assert.Equal(t, "│hi      │", lines[1])
+	})
+
+	t.Run("margins count toward the width", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _ = Box().FullWidth().MarginX(2).Fprint(&buf, "hi")
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, 20, visibleWidth(lines[0]))
+		assert.Equal(t, "  ┌──────────────┐  ", lines[0])
+	})
+
+	t.Run("wraps wide content like Width", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, _ = Box().WidthPercent(50).Fprint(&buf, "the quick brown fox")
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, "│the     │", lines[1])
+		assert.Equal(t, "│quick   │", lines[2])
+		assert.Equal(t, Box().Width(10).String("the quick brown fox"), buf.String())
+	})
+
+	t.Run("String resolves against default output", func(t *testing.T) {
+		var buf bytes.Buffer
+		SetOutput(&buf)
+		defer SetOutput(nil)
+		got := Box().FullWidth().Center().String("hi")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│        hi        │", lines[1])
+	})
+}
+
+func TestBoxWidth(t *testing.T) {
+	t.Run("pads short content to the width", func(t *testing.T) {
+		got := Box().Width(8).String("hi")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌──────┐", lines[0])
+		assert.Equal(t, "│hi    │", lines[1])
+	})
+
+	t.Run("wraps long content", func(t *testing.T) {
+		got := Box().Width(9).PaddingX(1).String("the quick brown fox")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌───────┐", lines[0])
+		assert.Equal(t, "│ the   │", lines[1])
+		assert.Equal(t, "│ quick │", lines[2])
+		assert.Equal(t, "│ brown │", lines[3])
+		assert.Equal(t, "│ fox   │", lines[4])
+		assert.Equal(t, "└───────┘", lines[5])
+	})
+
+	t.Run("breaks words longer than a line", func(t *testing.T) {
+		got := Box().Width(5).String("abcdefg")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│abc│", lines[1])
+		assert.Equal(t, "│def│", lines[2])
+		assert.Equal(t, "│g  │", lines[3])
+	})
+
+	t.Run("margins count toward the width", func(t *testing.T) {
+		got := Box().Width(8).MarginLeft(2).String("hi")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "  ┌────┐", lines[0])
+	})
+
+	t.Run("width and percent replace each other", func(t *testing.T) {
+		b := Box().WidthPercent(50).Width(6)
+		assert.Equal(t, 0, b.widthPercent)
+		assert.Equal(t, 6, b.width)
+		b = b.WidthPercent(50)
+		assert.Equal(t, 0, b.width)
+	})
+}
+
+func TestBorderJunctions(t *testing.T) {
+	presets := map[string]Border{
+		"simple":        BorderSimple,
+		"dashed":        BorderDashed,
+		"dotted":        BorderDotted,
+		"rounded":       BorderRounded,
+		"roundedDashed": BorderRoundedDashed,
+		"roundedDotted": BorderRoundedDotted,
+		"double":        BorderDouble,
+		"heavy":         BorderHeavy,
+		"doubleSingle":  BorderDoubleSingle,
+		"heavySingle":   BorderHeavySingle,
+		"ascii":         BorderASCII,
+		"block":         BorderBlock,
+		"blockHalf":     BorderBlockHalf,
+		"blockLight":    BorderBlockLight,
+		"blockMedium":   BorderBlockMedium,
+		"blockDark":     BorderBlockDark,
+	}
+	for name, b := range presets {
+		t.Run(name+" fills every junction", func(t *testing.T) {
+			for _, g := range []string{b.MiddleLeft, b.MiddleRight, b.MiddleTop, b.MiddleBottom, b.Cross, b.InnerHorizontal, b.InnerVertical} {
+				assert.Equal(t, 1, visibleWidth(g))
+			}
+		})
+	}
+
+	t.Run("frame junctions", func(t *testing.T) {
+		b := BorderSimple
+		assert.Equal(t, "┌", b.junction(edgeDown|edgeRight, edgeUp|edgeLeft))
+		assert.Equal(t, "┬", b.junction(edgeLeft|edgeRight|edgeDown, edgeUp))
+		assert.Equal(t, "├", b.junction(edgeUp|edgeDown|edgeRight, edgeLeft))
+		assert.Equal(t, "│", b.junction(edgeUp|edgeDown, edgeLeft))
+		assert.Equal(t, "┼", b.junction(edgeUp|edgeDown|edgeLeft|edgeRight, 0))
+		assert.Equal(t, "", b.junction(0, 0))
+	})
+
+	t.Run("mixed weight junctions", func(t *testing.T) {
+		b := BorderDoubleSingle
+		assert.Equal(t, "╤", b.junction(edgeLeft|edgeRight|edgeDown, edgeUp))
+		assert.Equal(t, "╟", b.junction(edgeUp|edgeDown|edgeRight, edgeLeft))
+		assert.Equal(t, "┼", b.junction(edgeUp|edgeDown|edgeLeft|edgeRight, 0))
+		assert.Equal(t, "┴", b.junction(edgeLeft|edgeRight|edgeUp, 0))
+		assert.Equal(t, "─", b.junction(edgeLeft|edgeRight, 0))
+	})
+
+	t.Run("custom border without junctions falls back", func(t *testing.T) {
+		b := Border{TopLeft: "┏", Top: "━", Left: "┃"}
+		assert.Equal(t, "┳", b.junction(edgeLeft|edgeRight|edgeDown, edgeUp))
+		assert.Equal(t, "╋", b.junction(edgeUp|edgeDown|edgeLeft|edgeRight, 0))
+		assert.Equal(t, "━", b.junction(edgeLeft|edgeRight, 0))
+
+		ascii := Border{TopLeft: "*", Top: "=", Left: "!"}
+		assert.Equal(t, "*", ascii.junction(edgeUp|edgeDown|edgeLeft|edgeRight, 0))
+	})
+}
+
+func TestBoxDividers(t *testing.T) {
+	t.Run("sections are separated by rules", func(t *testing.T) {
+		got := Box().PaddingX(1).Sections("Header", "body", "sum")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌────────┐", lines[0])
+		assert.Equal(t, "│ Header │", lines[1])
+		assert.Equal(t, "├────────┤", lines[2])
+		assert.Equal(t, "│ body   │", lines[3])
+		assert.Equal(t, "├────────┤", lines[4])
+		assert.Equal(t, "│ sum    │", lines[5])
+		assert.Equal(t, "└────────┘", lines[6])
+	})
+
+	t.Run("labeled divider", func(t *testing.T) {
+		got := Box().Border(BorderDouble).Section("Body", AlignCenter, "x").String("Head")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "╠═Body═╣", lines[2])
+	})
+
+	t.Run("label widens the box", func(t *testing.T) {
+		got := Box().Section("long", AlignLeft, "b").String("a")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌──────┐", lines[0])
+		assert.Equal(t, "├─long─┤", lines[2])
+		assert.Equal(t, "│b     │", lines[3])
+	})
+
+	t.Run("mixed border uses inner glyphs", func(t *testing.T) {
+		got := Box().Border(BorderDoubleSingle).Sections("a", "b")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "╟─╢", lines[2])
+	})
+
+	t.Run("disabled sides hide junctions", func(t *testing.T) {
+		got := Box().DisableLeft().Sections("a", "b")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, " ─┤", lines[2])
+	})
+
+	t.Run("divider ignores wrapping", func(t *testing.T) {
+		got := Box().Width(5).Sections("abc def", "x")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│abc│", lines[1])
+		assert.Equal(t, "│def│", lines[2])
+		assert.Equal(t, "├───┤", lines[3])
+	})
+
+	t.Run("labels are plain text", func(t *testing.T) {
+		label := "\x1b]8;;https://x\x07a\x1b]8;;\x07"
+		got := Box().Section(label, AlignLeft, "b").String("a")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "├─"+label+"─┤", lines[2])
+		assert.Equal(t, "│b  │", lines[3])
+	})
+
+	t.Run("content is never a divider", func(t *testing.T) {
+		got := Box().String("a\n\x1b]tinta-divider;0;x\x07")
+		assert.Equal(t, 4, len(strings.Split(got, "\n")))
+		assert.Equal(t, false, strings.Contains(got, "├"))
+	})
+
+	t.Run("sections are immutable", func(t *testing.T) {
+		base := Box().Section("", AlignLeft, "b")
+		_ = base.Section("", AlignLeft, "c")
+		assert.Equal(t, "┌─┐\n│a│\n├─┤\n│b│\n└─┘", base.String("a"))
+	})
+
+	t.Run("sections resolve the writer's width", func(t *testing.T) {
+		t.Setenv("COLUMNS", "8")
+		var buf bytes.Buffer
+		_, _ = Box().FullWidth().Section("", AlignLeft, "b").Fprint(&buf, "a")
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, "├──────┤", lines[2])
+		assert.Equal(t, "│b     │", lines[3])
+	})
+}
+
+func TestBoxShadow(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("shadow extends the box", func(t *testing.T) {
+		got := Box().Shadow(1, 1, ShadowLight).String("hi")
+		assert.Equal(t, "┌──┐ \n│hi│░\n└──┘░\n ░░░░", got)
+	})
+
+	t.Run("margins surround the shadow", func(t *testing.T) {
+		got := Box().MarginLeft(1).MarginRight(1).Shadow(2, 1, ShadowDark).String("a")
+		assert.Equal(t, " ┌─┐   \n │a│▓▓ \n └─┘▓▓ \n   ▓▓▓ ", got)
+	})
+
+	t.Run("no shadow by default", func(t *testing.T) {
+		assert.Equal(t, "┌──┐\n│hi│\n└──┘", Box().String("hi"))
+	})
+
+	t.Run("full width includes the shadow", func(t *testing.T) {
+		t.Setenv("COLUMNS", "10")
+		for _, dx := range []int{2, -2} {
+			var buf bytes.Buffer
+			_, _ = Box().FullWidth().MarginX(1).Shadow(dx, 1, ShadowLight).Fprint(&buf, "hi")
+			for _, line := range strings.Split(buf.String(), "\n") {
+				assert.Equal(t, 10, visibleWidth(line))
+			}
+		}
+	})
+}
@@ -7,26 +7,62 @@ import (
 
 type cell struct {
 	r     rune
-	style string
+	style sgr
+	// clear cells are see-through: compositing leaves the cell below them
+	// visible.
+	clear bool
 }
 
 type layer struct {
+	id   string
 	grid [][]cell
 	x, y int
 	z    int
 	seq  int
+
+	// anchor is set on layers added with Place; x and y then hold the
+	// offset from the anchored position.
+	anchor *Anchor
+	hidden bool
+	clip   *rect
+
+	// prepare marks the see-through cells of a freshly parsed grid; it is
+	// kept so that Replace treats new content like the original.
+	prepare func([][]cell)
+
+	// edges is set on layers drawn with the line methods. Only cells with
+	// arms are drawn; they merge with the lines below them.
+	edges   [][]lineEdges
+	rounded bool
+
+	// blended layers mix their colors with the cells below.
+	blended bool
+	blend   BlendMode
+	alpha   float64
+
+	// transforms are the flips, rotations and scalings applied to the
+	// layer, in order, kept so that Replace applies them to new content.
+	transforms []transform
 }
 
 // CanvasStyle holds layers and compositing settings. Create one with
-// [Canvas] and chain Add/AddZ/Width/Height methods. Call [CanvasStyle.String]
-// to composite all layers into a final string.
+// [Canvas] and chain methods that add layers (Add, AddZ, Place, HLine, ...)
+// or set compositing options (Width, Height, Viewport, TabWidth,
+// CollapseBorders, ...). Call [CanvasStyle.String] to composite all layers
+// into a final string.
 //
 // All methods return a new CanvasStyle to preserve immutability.
 type CanvasStyle struct {
-	layers []layer
-	width  int
-	height int
-	nextZ  int
+	layers     []layer
+	width      int
+	height     int
+	nextZ      int
+	lastAdd    int
+	viewport   *rect
+	crop       *rect
+	background *background
+	tabWidth   int
+	collapse   bool
 }
 
 // Canvas returns a new empty [CanvasStyle].
@@ -54,21 +90,109 @@ func (c *CanvasStyle) Add(s string, x, y int) *CanvasStyle {
 // Higher z values are drawn on top of lower ones. When two layers share the
 // same z, insertion order wins (later Add calls draw on top).
 func (c *CanvasStyle) AddZ(s string, x, y, z int) *CanvasStyle {
+	return c.addLayer(layer{grid: c.parse(s), x: x, y: y, z: z})
+}
+
+// AddCanvas places the composited cells of child as a single layer, with
+// the child's point (0, 0) at (x, y), without rendering it to a string.
+// Child layers at negative coordinates therefore extend above and to the
+// left of (x, y), and a child viewport or crop only cuts the child, which
+// stays in place. The child keeps its own z-order, clipping and
+// transparency: positions no child layer draws on are see-through.
+// Blending and line merging happen within the child, against its own
+// layers only.
+func (c *CanvasStyle) AddCanvas(child *CanvasStyle, x, y int) *CanvasStyle {
+	grid, originX, originY := child.cells()
+	return c.addLayer(layer{grid: grid, x: x + originX, y: y + originY, z: c.nextZ})
+}
+
+// AddTransparent places a rendered string like [CanvasStyle.Add], but
+// treats its unstyled spaces as see-through: the layers below show
+// through them instead of being erased. Spaces carrying a style, such as
+// a background color, stay opaque.
+func (c *CanvasStyle) AddTransparent(s string, x, y int) *CanvasStyle {
+	return c.AddTransparentRune(s, x, y, ' ')
+}
+
+// AddTransparentRune places a rendered string like [CanvasStyle.Add], but
+// treats unstyled cells holding r as see-through, so any rune can act as
+// the transparent color key of a layer.
+func (c *CanvasStyle) AddTransparentRune(s string, x, y int, r rune) *CanvasStyle {
+	prepare := func(grid [][]cell) {
+		for _, row := range grid {
+			for i := range row {
+				if row[i].r == r && row[i].style.isZero() {
+					row[i].clear = true
+				}
+			}
+		}
+	}
+	grid := c.parse(s)
+	prepare(grid)
+	return c.addLayer(layer{grid: grid, x: x, y: y, z: c.nextZ, prepare: prepare})
+}
+
+// AddMasked places a rendered string like [CanvasStyle.Add], keeping only
+// the cells selected by mask. The mask is laid over the layer with the
+// same origin: cells under a non-space mask rune are drawn, while cells
+// under a space, or outside the mask, are see-through. Escape sequences
+// in the mask are ignored.
+func (c *CanvasStyle) AddMasked(s, mask string, x, y int) *CanvasStyle {
+	keep := c.parse(mask)
+	prepare := func(grid [][]cell) {
+		for rowIdx, row := range grid {
+			for colIdx := range row {
+				row[colIdx].clear = rowIdx >= len(keep) ||
+					colIdx >= len(keep[rowIdx]) ||
+					keep[rowIdx][colIdx].r == ' '
+			}
+		}
+	}
+	grid := c.parse(s)
+	prepare(grid)
+	return c.addLayer(layer{grid: grid, x: x, y: y, z: c.nextZ, prepare: prepare})
+}
+
+func (c *CanvasStyle) parse(s string) [][]cell {
+	return parseGrid(expandTabs(s, c.tabWidth))
+}
+
+// addLayer appends ly with the next sequence number, keeping nextZ above
+// every z in use.
+func (c *CanvasStyle) addLayer(ly layer) *CanvasStyle {
 	cp := copyCanvas(c)
-	grid := parseGrid(s)
-	cp.layers = append(cp.layers, layer{
-		grid: grid,
-		x:    x,
-		y:    y,
-		z:    z,
-		seq:  len(cp.layers),
-	})
-	if z >= cp.nextZ {
-		cp.nextZ = z + 1
+	cp.lastAdd = len(cp.layers)
+	ly.seq = 0
+	if n := len(cp.layers); n > 0 {
+		ly.seq = cp.layers[n-1].seq + 1
+	}
+	cp.layers = append(cp.layers, ly)
+	if ly.z >= cp.nextZ {
+		cp.nextZ = ly.z + 1
 	}
 	return cp
 }
 
+// TabWidth sets the tab stop interval used to expand tab characters in
+// layers added afterwards. Tab stops are measured from the left edge of
+// each layer. Values below 1 restore the default of 8.
+func (c *CanvasStyle) TabWidth(n int) *CanvasStyle {
+	cp := copyCanvas(c)
+	cp.tabWidth = n
+	return cp
+}
+
+// CollapseBorders makes touching box borders merge instead of overwriting
+// each other. When a box-drawing glyph is drawn over another one, the cell
+// gets the glyph that has the arms of both, so two boxes sharing an edge
+// show a single line with T and cross junctions where their corners meet.
+// The upper cell's style is kept.
+func (c *CanvasStyle) CollapseBorders() *CanvasStyle {
+	cp := copyCanvas(c)
+	cp.collapse = true
+	return cp
+}
+
 // Width sets a fixed canvas width. If zero (default), the width is
 // derived from the rightmost visible cell across all layers.
 func (c *CanvasStyle) Width(w int) *CanvasStyle {
@@ -87,9 +211,15 @@ func (c *CanvasStyle) Height(h int) *CanvasStyle {
 
 // String composites all layers and returns the final rendered string.
 // Layers are drawn in z-order (ascending), then by insertion order.
-// Each layer is fully opaque: every cell in a layer's grid overwrites
-// whatever is below it. Positions not covered by any layer are rendered
-// as plain spaces. The result has no trailing newline on the last row.
+// Layers are opaque unless added with [CanvasStyle.AddTransparent],
+// [CanvasStyle.AddTransparentRune] or [CanvasStyle.AddMasked], or blended
+// with [CanvasStyle.AddOpacity] or [CanvasStyle.AddBlend]: every other
+// cell overwrites whatever is below it, except that box-drawing glyphs
+// merge when [CanvasStyle.CollapseBorders] is set. Lines drawn with the
+// line methods only cover the cells on the line and always merge with
+// lines below. Positions not covered by any layer are rendered as plain
+// spaces, or with the [CanvasStyle.Background]. The result has no
+// trailing newline on the last row.
 //
 // When auto-sizing (Width/Height not set), the canvas expands to fit all
 // layer content, including layers at negative x/y positions. The origin
@@ -97,14 +227,41 @@ func (c *CanvasStyle) Height(h int) *CanvasStyle {
 //
 // When Width or Height are explicitly set, those fixed dimensions are
 // applied after the origin shift, which can crop content that falls
-// outside the fixed bounds.
+// outside the fixed bounds. A [CanvasStyle.Viewport] replaces both the
+// origin shift and the fixed size with its window.
 func (c *CanvasStyle) String() string {
-	if len(c.layers) == 0 {
+	grid, _, _ := c.cells()
+	if grid == nil {
 		return ""
 	}
 
-	sorted := make([]layer, len(c.layers))
-	copy(sorted, c.layers)
+	var buf strings.Builder
+	for rowIdx, row := range grid {
+		if rowIdx > 0 {
+			buf.WriteByte('\n')
+		}
+
+		writeCells(&buf, trimCells(row))
+	}
+
+	return buf.String()
+}
+
+// cells composites all layers into a grid of cells, as described for
+// [CanvasStyle.String]. Positions not covered by any layer are blank
+// see-through cells. It also returns the canvas coordinates of the
+// top-left cell. The grid is nil when there is nothing to draw.
+func (c *CanvasStyle) cells() (grid [][]cell, originX, originY int) {
+	if len(c.layers) == 0 && c.background == nil {
+		return nil, 0, 0
+	}
+
+	var sorted []layer
+	for _, ly := range c.resolve() {
+		if !ly.hidden {
+			sorted = append(sorted, ly)
+		}
+	}
 	sort.SliceStable(sorted, func(i, j int) bool {
 		if sorted[i].z != sorted[j].z {
 			return sorted[i].z < sorted[j].z
@@ -112,70 +269,88 @@ func (c *CanvasStyle) String() string {
 		return sorted[i].seq < sorted[j].seq
 	})
 
-	minX, minY := 0, 0
-	maxX, maxY := 0, 0
-	hasContent := false
-	for _, ly := range sorted {
-		for rowIdx, row := range ly.grid {
-			cy := ly.y + rowIdx
-			for colIdx := range row {
-				cx := ly.x + colIdx
-				if !hasContent {
-					minX, minY = cx, cy
-					maxX, maxY = cx, cy
-					hasContent = true
-				} else {
-					if cx < minX {
-						minX = cx
+	var shiftX, shiftY, w, h int
+	if v := c.viewport; v != nil {
+		shiftX, shiftY, w, h = -v.x, -v.y, v.w, v.h
+	} else {
+		minX, minY := 0, 0
+		maxX, maxY := 0, 0
+		hasContent := false
+		for _, ly := range sorted {
+			for rowIdx, row := range ly.grid {
+				cy := ly.y + rowIdx
+				for colIdx := range row {
+					cx := ly.x + colIdx
+					if ly.clip != nil && !ly.clip.contains(cx, cy) {
+						continue
 					}
-					if cy < minY {
-						minY = cy
-					}
-					if cx > maxX {
-						maxX = cx
-					}
-					if cy > maxY {
-						maxY = cy
+					if !hasContent {
+						minX, minY = cx, cy
+						maxX, maxY = cx, cy
+						hasContent = true
+					} else {
+						if cx < minX {
+							minX = cx
+						}
+						if cy < minY {
+							minY = cy
+						}
+						if cx > maxX {
+							maxX = cx
+						}
+						if cy > maxY {
+							maxY = cy
+						}
 					}
 				}
 			}
 		}
-	}
 
-	if !hasContent {
-		return ""
-	}
+		// A background alone fills a canvas only when its size is fixed.
+		if !hasContent && (c.background == nil || c.width == 0 || c.height == 0) {
+			return nil, 0, 0
+		}
 
-	shiftX := 0
-	if minX < 0 {
-		shiftX = -minX
-	}
-	shiftY := 0
-	if minY < 0 {
-		shiftY = -minY
-	}
+		if minX < 0 {
+			shiftX = -minX
+		}
+		if minY < 0 {
+			shiftY = -minY
+		}
 
-	w := c.width
-	h := c.height
-	if w == 0 {
-		w = maxX + shiftX + 1
-	}
-	if h == 0 {
-		h = maxY + shiftY + 1
+		w = c.width
+		h = c.height
+		if w == 0 {
+			w = maxX + shiftX + 1
+		}
+		if h == 0 {
+			h = maxY + shiftY + 1
+		}
 	}
 
 	if w <= 0 || h <= 0 {
-		return ""
+		return nil, 0, 0
 	}
 
-	grid := make([][]cell, h)
-	for i := range grid {
-		grid[i] = make([]cell, w)
-		for j := range grid[i] {
-			grid[i][j] = cell{r: ' '}
+	if c.background != nil {
+		grid = c.background.fill(w, h)
+	} else {
+		grid = make([][]cell, h)
+		for i := range grid {
+			grid[i] = make([]cell, w)
+			for j := range grid[i] {
+				grid[i][j] = cell{r: ' ', clear: true}
+			}
 		}
 	}
 
+	// edges tracks the arms of cells drawn by line layers, so that lines
+	// keep their exact weights when crossed by later lines.
+	edges := make([][]lineEdges, h)
+	for i := range edges {
+		edges[i] = make([]lineEdges, w)
+	}
+
 	for _, ly := range sorted {
 		for rowIdx, row := range ly.grid {
 			cy := ly.y + rowIdx + shiftY
@@ -187,41 +362,91 @@ func (c *CanvasStyle) String() string {
 				if cx < 0 || cx >= w {
 					continue
 				}
+				if ly.clip != nil && !ly.clip.contains(cx-shiftX, cy-shiftY) {
+					continue
+				}
+				if ly.edges != nil {
+					arms := ly.edges[rowIdx][colIdx]
+					if arms == (lineEdges{}) {
+						continue
+					}
+					below := edges[cy][cx]
+					if below == (lineEdges{}) {
+						below, _ = edgesOf(grid[cy][cx].r)
+					}
+					for i, weight := range arms {
+						if weight == 0 {
+							arms[i] = below[i]
+						}
+					}
+					edges[cy][cx] = arms
+					grid[cy][cx] = cell{
+						r:     lineGlyph(arms, ly.rounded),
+						style: sgr{}.over(grid[cy][cx].style),
+					}
+					continue
+				}
+				if cl.clear {
+					continue
+				}
+				if ly.blended {
+					out := blendCell(grid[cy][cx], cl, ly.blend, ly.alpha)
+					if out.r != grid[cy][cx].r {
+						edges[cy][cx] = lineEdges{}
+					}
+					grid[cy][cx] = out
+					continue
+				}
+				edges[cy][cx] = lineEdges{}
+				if c.collapse {
+					cl.r = mergeLines(grid[cy][cx].r, cl.r)
+				}
+				cl.style = cl.style.over(grid[cy][cx].style)
 				grid[cy][cx] = cl
 			}
 		}
 	}
 
-	var buf strings.Builder
-	for rowIdx, row := range grid {
-		if rowIdx > 0 {
-			buf.WriteByte('\n')
-		}
+	if r := c.crop; r != nil {
+		return cropGrid(grid, *r), r.x - shiftX, r.y - shiftY
+	}
+	return grid, -shiftX, -shiftY
+}
 
-		lastVisible := len(row) - 1
-		for lastVisible >= 0 && row[lastVisible].r == ' ' && row[lastVisible].style == "" {
-			lastVisible--
-		}
+// trimCells returns row without its trailing unstyled spaces.
+func trimCells(row []cell) []cell {
+	last := len(row) - 1
+	for last >= 0 && row[last].r == ' ' && row[last].style.isZero() {
+		last--
+	}
+	return row[:last+1]
+}
 
-		lastStyle := ""
-		for colIdx := 0; colIdx <= lastVisible; colIdx++ {
-			cl := row[colIdx]
-			if cl.style != lastStyle {
-				if lastStyle != "" {
-					buf.WriteString(cReset)
-				}
-				if cl.style != "" {
-					buf.WriteString(cl.style)
-				}
-				lastStyle = cl.style
-			}
-			buf.WriteRune(cl.r)
-		}
-		if lastStyle != "" {
-			buf.WriteString(cReset)
-		}
+// cellWriter is implemented by *strings.Builder and *bytes.Buffer.
+type cellWriter interface {
+	WriteString(s string) (int, error)
+	WriteByte(c byte) error
+	WriteRune(r rune) (int, error)
+}
+
+// writeCells writes the runes of cells to buf, switching styles with the
+// shortest transition from the previous cell's style and emitting a reset
+// after the last styled cell.
+func writeCells(buf cellWriter, cells []cell) {
+	var lastStyle sgr
+	for _, cl := range cells {
+		cl.style.writeTransition(buf, lastStyle)
+		lastStyle = cl.style
+		buf.WriteRune(cl.r)
+	}
+	if !lastStyle.isZero() {
+		buf.WriteString(cReset)
 	}
+}
 
+func encodeCells(cells []cell) string {
+	var buf strings.Builder
+	writeCells(&buf, cells)
 	return buf.String()
 }
 
@@ -244,64 +469,32 @@ func parseGrid(s string) [][]cell {
 
 func parseLine(line string) []cell {
 	var cells []cell
-	var styleBuf strings.Builder
+	scanLine(line, func(cl cell) { cells = append(cells, cl) })
+	return cells
+}
+
+// scanLine calls emit with each cell of line, in order, without building
+// a slice.
+func scanLine(line string, emit func(cell)) {
+	var style sgr
 
 	j := 0
-	runes := []byte(line)
-	n := len(runes)
+	n := len(line)
 
 	for j < n {
-		if runes[j] == '\x1b' {
+		if line[j] == '\x1b' {
 			start := j
-			if j+1 < n {
-				switch runes[j+1] {
-				case '[':
-					j += 2
-					for j < n {
-						if runes[j] >= 0x40 && runes[j] <= 0x7E {
-							j++
-							break
-						}
-						j++
-					}
-				case ']':
-					j += 2
-					for j < n {
-						if runes[j] == '\x07' {
-							j++
-							break
-						}
-						if runes[j] == '\x1b' && j+1 < n && runes[j+1] == '\\' {
-							j += 2
-							break
-						}
-						j++
-					}
-				default:
-					j += 2
-				}
-			} else {
-				j++
-			}
-			seq := line[start:j]
-
-			if seq == cReset {
-				styleBuf.Reset()
-			} else {
-				styleBuf.WriteString(seq)
+			j += escapeLen(line, j)
+			if seq := line[start:j]; !style.apply(seq) {
+				style.extra += seq
 			}
 			continue
 		}
 
 		r, size := decodeRune(line[j:])
-		cells = append(cells, cell{
-			r:     r,
-			style: styleBuf.String(),
-		})
+		emit(cell{r: r, style: style})
 		j += size
 	}
-
-	return cells
 }
 
 func decodeRune(s string) (rune, int) {
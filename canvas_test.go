@@ -380,3 +380,218 @@ func TestCanvasMultipleDepths(t *testing.T) {
 		assert.Equal(t, 7, len(lines))
 	})
 }
+
+func TestCanvasTabWidth(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("default tab width is 8", func(t *testing.T) {
+		got := Canvas().Add("a\tb", 0, 0).String()
+		assert.Equal(t, "a       b", got)
+	})
+
+	t.Run("custom tab width", func(t *testing.T) {
+		got := Canvas().TabWidth(2).Add("a\tb", 0, 0).String()
+		assert.Equal(t, "a b", got)
+	})
+
+	t.Run("tab stops are relative to the layer", func(t *testing.T) {
+		got := Canvas().TabWidth(4).Add("a\tb", 3, 0).String()
+		assert.Equal(t, "   a   b", got)
+	})
+}
+
+func TestCanvasCollapseBorders(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	box := Box().String("ab")
+
+	t.Run("overlapping boxes overwrite by default", func(t *testing.T) {
+		got := Canvas().Add(box, 0, 0).Add(box, 3, 0).String()
+		assert.Equal(t, "┌──┌──┐\n│ab│ab│\n└──└──┘", got)
+	})
+
+	t.Run("shared edge becomes junctions", func(t *testing.T) {
+		got := Canvas().CollapseBorders().Add(box, 0, 0).Add(box, 3, 0).String()
+		assert.Equal(t, "┌──┬──┐\n│ab│ab│\n└──┴──┘", got)
+	})
+
+	t.Run("crossing lines form a cross", func(t *testing.T) {
+		got := Canvas().CollapseBorders().Add("─\n\n", 0, 1).Add("│\n│\n│", 0, 0).String()
+		assert.Equal(t, "│\n┼\n│", got)
+	})
+
+	t.Run("text is not merged", func(t *testing.T) {
+		got := Canvas().CollapseBorders().Add("│", 0, 0).Add("x", 0, 0).String()
+		assert.Equal(t, "x", got)
+	})
+
+	t.Run("upper style is kept", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().CollapseBorders().Add("│", 0, 0).Add("\x1b[31m─\x1b[0m", 0, 0).String()
+		assert.Equal(t, "\x1b[31m┼\x1b[0m", got)
+	})
+}
+
+func TestCanvasTransparency(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	panel := Box().String("abc")
+
+	t.Run("spaces erase by default", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add("x x", 1, 1).String()
+		assert.Equal(t, "┌───┐\n│x x│\n└───┘", got)
+	})
+
+	t.Run("transparent spaces show the layer below", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).AddTransparent("x x", 1, 1).String()
+		assert.Equal(t, "┌───┐\n│xbx│\n└───┘", got)
+	})
+
+	t.Run("styled spaces stay opaque", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().Add("abc", 0, 0).AddTransparent("\x1b[41m \x1b[0m  ", 0, 0).String()
+		assert.Equal(t, "\x1b[41m \x1b[0mbc", got)
+	})
+
+	t.Run("transparent rune", func(t *testing.T) {
+		got := Canvas().Add("abc", 0, 0).AddTransparentRune(".X.", 0, 0, '.').String()
+		assert.Equal(t, "aXc", got)
+	})
+
+	t.Run("mask selects cells", func(t *testing.T) {
+		got := Canvas().Add("abc\ndef", 0, 0).AddMasked("XXX\nXXX", " #\n##", 0, 0).String()
+		assert.Equal(t, "aXc\nXXf", got)
+	})
+
+	t.Run("badge over a border", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).AddTransparent(" 1", 2, 0).String()
+		assert.Equal(t, "┌──1┐\n│abc│\n└───┘", got)
+	})
+}
+
+func TestCanvasStyleCompositing(t *testing.T) {
+	ForceColors(true)
+
+	panel := Text().OnBlue().String("     ")
+
+	t.Run("text keeps the panel background", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add(Text().Red().String("hi"), 1, 0).String()
+		assert.Equal(t, "\x1b[44m \x1b[31mhi\x1b[39m  \x1b[0m", got)
+	})
+
+	t.Run("unstyled text keeps the panel background", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add("hi", 1, 0).String()
+		assert.Equal(t, "\x1b[44m     \x1b[0m", strings.ReplaceAll(got, "hi", "  "))
+	})
+
+	t.Run("own background wins", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add(Text().OnRed().String("x"), 0, 0).String()
+		assert.Equal(t, "\x1b[41mx\x1b[44m    \x1b[0m", got)
+	})
+
+	t.Run("flags are not inherited", func(t *testing.T) {
+		got := Canvas().Add(Text().Bold().String("ab"), 0, 0).Add("x", 0, 0).String()
+		assert.Equal(t, "x\x1b[1mb\x1b[0m", got)
+	})
+}
+
+func TestCanvasMinimalTransitions(t *testing.T) {
+	ForceColors(true)
+
+	t.Run("only changed attributes are written", func(t *testing.T) {
+		row := Text().Red().Bold().String("a") + Text().Bold().String("b") + Text().Bold().Underline().String("c")
+		got := Canvas().Add(row, 0, 0).String()
+		assert.Equal(t, "\x1b[1;31ma\x1b[39mb\x1b[4mc\x1b[0m", got)
+	})
+
+	t.Run("reset when shorter", func(t *testing.T) {
+		row := Text().Bold().Italic().Underline().Red().String("a") + Text().OnBlue().String("b")
+		got := Canvas().Add(row, 0, 0).String()
+		assert.Equal(t, "\x1b[1;3;4;31ma\x1b[0;44mb\x1b[0m", got)
+	})
+
+	t.Run("unknown attributes are kept", func(t *testing.T) {
+		row := "\x1b[4:3;58;5;1mab\x1b[0mc"
+		assert.Equal(t, row, Canvas().Add(row, 0, 0).String())
+
+		row = "\x1b[1mA\x1b[?1mB"
+		assert.Equal(t, row+"\x1b[0m", Canvas().Add(row, 0, 0).String())
+	})
+
+	t.Run("curly underline can be turned off", func(t *testing.T) {
+		got := Canvas().Add("\x1b[4:3ma\x1b[24mb\x1b[4:3mc\x1b[4:0md\x1b[4:3me\x1b[0mf", 0, 0).String()
+		assert.Equal(t, "\x1b[4:3ma\x1b[0mb\x1b[4:3mc\x1b[0md\x1b[4:3me\x1b[0mf", got)
+	})
+
+	t.Run("underline colors do not accumulate", func(t *testing.T) {
+		got := Canvas().Add("\x1b[58;5;1ma\x1b[59mb\x1b[58;5;2mc\x1b[59md", 0, 0).String()
+		assert.Equal(t, "\x1b[58;5;1ma\x1b[0mb\x1b[58;5;2mc\x1b[0md", got)
+	})
+
+	t.Run("output parses back to the same cells", func(t *testing.T) {
+		row := Text().Dim().Bold().Magenta().String("ab") + Text().Dim().String("c") +
+			"d" + Text().OnBlue().Strike().String("e")
+		want := parseLine(row)
+		assert.Equal(t, want, parseLine(Canvas().Add(row, 0, 0).String()))
+	})
+}
+
+func TestCanvasAddCanvas(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("child is placed as a layer", func(t *testing.T) {
+		child := Canvas().Add(Box().String("a"), 0, 0)
+		got := Canvas().Add("......\n......\n......", 0, 0).AddCanvas(child, 2, 0).String()
+		assert.Equal(t, "..┌─┐.\n..│a│.\n..└─┘.", got)
+	})
+
+	t.Run("uncovered child cells are see-through", func(t *testing.T) {
+		child := Canvas().Add("x", 0, 0).Add("y", 2, 1)
+		got := Canvas().Add("....\n....", 0, 0).AddCanvas(child, 1, 0).String()
+		assert.Equal(t, ".x..\n...y", got)
+	})
+
+	t.Run("child keeps its own z-order", func(t *testing.T) {
+		child := Canvas().AddZ("top", 0, 0, 9).AddZ("bottom", 0, 0, 1)
+		assert.Equal(t, "toptom", Canvas().AddCanvas(child, 0, 0).String())
+	})
+
+	t.Run("child keeps its transparency and clipping", func(t *testing.T) {
+		child := Canvas().Add("abcd", 0, 0).Clip(0, 0, 2, 1).AddTransparent("  x", 0, 1)
+		got := Canvas().Add("....\n....", 0, 0).AddCanvas(child, 0, 0).String()
+		assert.Equal(t, "ab..\n..x.", got)
+	})
+
+	t.Run("child viewport", func(t *testing.T) {
+		child := Canvas().Add("abcdef", 0, 0).Viewport(2, 0, 2, 1)
+		got := Canvas().Add("......", 0, 0).AddCanvas(child, 1, 0).String()
+		assert.Equal(t, "...cd.", got)
+	})
+
+	t.Run("child origin lands at the position", func(t *testing.T) {
+		child := Canvas().Add("ab", -1, 0).Add("c", 1, 1)
+		got := Canvas().Add("....\n....", 0, 0).AddCanvas(child, 1, 0).String()
+		assert.Equal(t, "ab..\n..c.", got)
+
+		// Placed at the origin, the canvas grows to fit the child.
+		got = Canvas().Add("xy", 0, 0).AddCanvas(child, 0, 0).String()
+		assert.Equal(t, "aby\n  c", got)
+	})
+
+	t.Run("nesting is deep", func(t *testing.T) {
+		inner := Canvas().Add("i", 0, 0)
+		middle := Canvas().Add("m", 0, 0).AddCanvas(inner, 1, 0)
+		got := Canvas().AddCanvas(middle, 1, 0).AddCanvas(middle, 3, 1).String()
+		assert.Equal(t, " mi\n   mi", got)
+	})
+
+	t.Run("empty child", func(t *testing.T) {
+		assert.Equal(t, "ab", Canvas().Add("ab", 0, 0).AddCanvas(Canvas(), 0, 0).String())
+	})
+}
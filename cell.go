@@ -0,0 +1,90 @@
+package tinta
+
+import "strings"
+
+// Cell describes one composited position of a canvas, as returned by
+// [CanvasStyle.Cell].
+type Cell struct {
+	Rune rune
+	// Fg and Bg are the colors as SGR parameters, such as "31", "101" or
+	// "38;5;208". They are empty for the terminal default.
+	Fg, Bg string
+
+	Bold, Dim, Italic, Underline, Blink, Invert, Hidden, Strike bool
+}
+
+// String returns the rune with its style applied.
+func (c Cell) String() string {
+	var codes []string
+	for _, on := range []struct {
+		set  bool
+		code string
+	}{
+		{c.Bold, cBold}, {c.Dim, cDim}, {c.Italic, cItalic}, {c.Underline, cUnderline},
+		{c.Blink, cBlink}, {c.Invert, cInvert}, {c.Hidden, cHidden}, {c.Strike, cStrike},
+	} {
+		if on.set {
+			codes = append(codes, on.code)
+		}
+	}
+	if c.Fg != "" {
+		codes = append(codes, c.Fg)
+	}
+	if c.Bg != "" {
+		codes = append(codes, c.Bg)
+	}
+	return (&TextStyle{codes: codes}).render(string(c.Rune))
+}
+
+// exported returns the public description of cl.
+func (cl cell) exported() Cell {
+	p := cl.style
+	out := Cell{
+		Rune:      cl.r,
+		Bold:      p.flags&flagBold != 0,
+		Dim:       p.flags&flagDim != 0,
+		Italic:    p.flags&flagItalic != 0,
+		Underline: p.flags&flagUnderline != 0,
+		Blink:     p.flags&flagBlink != 0,
+		Invert:    p.flags&flagInvert != 0,
+		Hidden:    p.flags&flagHidden != 0,
+		Strike:    p.flags&flagStrike != 0,
+	}
+	out.Fg = strings.Join(p.fg.appendCode(nil, 30), ";")
+	out.Bg = strings.Join(p.bg.appendCode(nil, 40), ";")
+	return out
+}
+
+// Text adds a layer with s at (x, y) in the given style, which may be
+// nil. It saves building a styled string for a single label.
+func (c *CanvasStyle) Text(x, y int, s string, style *TextStyle) *CanvasStyle {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = styleWith(style, line)
+	}
+	return c.Add(strings.Join(lines, "\n"), x, y)
+}
+
+// SetCell adds a layer holding the single rune r at (x, y) in the given
+// style, which may be nil.
+func (c *CanvasStyle) SetCell(x, y int, r rune, style *TextStyle) *CanvasStyle {
+	return c.addLayer(layer{
+		grid: [][]cell{{{r: r, style: textSGR(style)}}},
+		x:    x,
+		y:    y,
+		z:    c.nextZ,
+	})
+}
+
+// Cell returns the composited rune and attributes at (x, y), in the
+// coordinates used by [CanvasStyle.Add]. Positions outside the rendered
+// canvas, or not covered by anything, are unstyled spaces. Each call
+// composites the whole canvas.
+func (c *CanvasStyle) Cell(x, y int) Cell {
+	grid, originX, originY := c.cells()
+	row, col := y-originY, x-originX
+	if row < 0 || row >= len(grid) || col < 0 || col >= len(grid[row]) {
+		return Cell{Rune: ' '}
+	}
+	return grid[row][col].exported()
+}
@@ -0,0 +1,74 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasText(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("plain label", func(t *testing.T) {
+		got := Canvas().Add("......", 0, 0).Text(1, 0, "hi", nil).String()
+		assert.Equal(t, ".hi...", got)
+	})
+
+	t.Run("every line is styled", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().Text(0, 0, "a\nb", Text().Red()).String()
+		assert.Equal(t, "\x1b[31ma\x1b[0m\n\x1b[31mb\x1b[0m", got)
+	})
+}
+
+func TestCanvasSetCell(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("plots cells", func(t *testing.T) {
+		c := Canvas()
+		for x := 0; x < 4; x++ {
+			c = c.SetCell(x, 3-x, '*', nil)
+		}
+		assert.Equal(t, "   *\n  *\n *\n*", c.String())
+	})
+
+	t.Run("styled cell", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().SetCell(1, 0, '@', Text().Bold().Green()).String()
+		assert.Equal(t, " \x1b[1;32m@\x1b[0m", got)
+	})
+}
+
+func TestCanvasCell(t *testing.T) {
+	ForceColors(true)
+
+	c := Canvas().
+		Add(Text().OnBlue().String("    "), 0, 0).
+		Text(1, 0, "x", Text().Red().Bold()).
+		Add("y", -2, 1)
+
+	t.Run("composited attributes", func(t *testing.T) {
+		got := c.Cell(1, 0)
+		assert.Equal(t, Cell{Rune: 'x', Fg: "31", Bg: "44", Bold: true}, got)
+		assert.Equal(t, "\x1b[1;31;44mx\x1b[0m", got.String())
+	})
+
+	t.Run("canvas coordinates", func(t *testing.T) {
+		assert.Equal(t, Cell{Rune: 'y'}, c.Cell(-2, 1))
+		assert.Equal(t, Cell{Rune: ' ', Bg: "44"}, c.Cell(3, 0))
+	})
+
+	t.Run("outside the canvas", func(t *testing.T) {
+		assert.Equal(t, Cell{Rune: ' '}, c.Cell(50, 50))
+		assert.Equal(t, Cell{Rune: ' '}, Canvas().Cell(0, 0))
+	})
+
+	t.Run("extended colors", func(t *testing.T) {
+		got := Canvas().Add("\x1b[38;5;208;48;2;1;2;3mz\x1b[0m", 0, 0).Cell(0, 0)
+		assert.Equal(t, Cell{Rune: 'z', Fg: "38;5;208", Bg: "48;2;1;2;3"}, got)
+	})
+}
@@ -0,0 +1,99 @@
+package tinta
+
+import "strings"
+
+// Pattern returns the rune drawn at (x, y) of a w x h area filled by
+// [CanvasStyle.BackgroundPattern] or [CanvasStyle.FillPattern].
+type Pattern func(x, y, w, h int) rune
+
+// Predefined fill patterns.
+var (
+	// PatternCheckerboard alternates full blocks and spaces.
+	PatternCheckerboard Pattern = func(x, y, w, h int) rune {
+		if (x+y)%2 == 0 {
+			return '█'
+		}
+		return ' '
+	}
+	// PatternDots puts a dot on every other column of every other row.
+	PatternDots Pattern = func(x, y, w, h int) rune {
+		if x%2 == 0 && y%2 == 0 {
+			return '·'
+		}
+		return ' '
+	}
+	// PatternGradient shades from empty on the left to full on the right.
+	PatternGradient Pattern = func(x, y, w, h int) rune {
+		if w <= 1 {
+			return shades[len(shades)-1]
+		}
+		return shades[x*(len(shades)-1)/(w-1)]
+	}
+)
+
+// shades are the steps of [PatternGradient], from empty to full.
+var shades = []rune(" ░▒▓█")
+
+// solid returns a pattern that is r everywhere.
+func solid(r rune) Pattern {
+	return func(x, y, w, h int) rune { return r }
+}
+
+// background fills the positions of a canvas not covered by any layer.
+type background struct {
+	pattern Pattern
+	style   *TextStyle
+}
+
+// Background fills the positions not covered by any layer with r in the
+// given style, which may be nil. Background cells are opaque: a nested
+// canvas with a background hides what is below it.
+func (c *CanvasStyle) Background(r rune, style *TextStyle) *CanvasStyle {
+	return c.BackgroundPattern(solid(r), style)
+}
+
+// BackgroundPattern fills the positions not covered by any layer with a
+// pattern, such as [PatternCheckerboard], in the given style. The pattern
+// spans the rendered canvas.
+func (c *CanvasStyle) BackgroundPattern(p Pattern, style *TextStyle) *CanvasStyle {
+	cp := copyCanvas(c)
+	cp.background = &background{pattern: p, style: style}
+	return cp
+}
+
+// FillRect adds a layer filling the w x h rectangle at (x, y) with r in
+// the given style, which may be nil.
+func (c *CanvasStyle) FillRect(x, y, w, h int, r rune, style *TextStyle) *CanvasStyle {
+	return c.FillPattern(x, y, w, h, solid(r), style)
+}
+
+// FillPattern adds a layer filling the w x h rectangle at (x, y) with a
+// pattern in the given style. The pattern spans the rectangle.
+func (c *CanvasStyle) FillPattern(x, y, w, h int, p Pattern, style *TextStyle) *CanvasStyle {
+	if w < 1 || h < 1 {
+		return c
+	}
+	lines := make([]string, h)
+	var row strings.Builder
+	for j := range lines {
+		row.Reset()
+		for i := 0; i < w; i++ {
+			row.WriteRune(p(i, j, w, h))
+		}
+		lines[j] = styleWith(style, row.String())
+	}
+	return c.addLayer(layer{grid: parseGrid(strings.Join(lines, "\n")), x: x, y: y, z: c.nextZ})
+}
+
+// fill returns the background cells of a w x h canvas.
+func (b *background) fill(w, h int) [][]cell {
+	style := textSGR(b.style)
+	grid := make([][]cell, h)
+	for y := range grid {
+		grid[y] = make([]cell, w)
+		for x := range grid[y] {
+			grid[y][x] = cell{r: b.pattern(x, y, w, h), style: style}
+		}
+	}
+	return grid
+}
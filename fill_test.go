@@ -0,0 +1,81 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestPatterns(t *testing.T) {
+	render := func(p Pattern, w, h int) string {
+		return Canvas().FillPattern(0, 0, w, h, p, nil).String()
+	}
+	assert.Equal(t, "█ █\n █", render(PatternCheckerboard, 3, 2))
+	assert.Equal(t, "· ·\n\n· ·", render(PatternDots, 3, 3))
+	assert.Equal(t, " ░▒▓█", render(PatternGradient, 5, 1))
+	assert.Equal(t, " ▒█", render(PatternGradient, 3, 1))
+	assert.Equal(t, "█", render(PatternGradient, 1, 1))
+}
+
+func TestCanvasBackground(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("fills uncovered positions", func(t *testing.T) {
+		got := Canvas().Width(4).Height(2).Background('.', nil).Add("ab", 1, 1).String()
+		assert.Equal(t, "....\n.ab.", got)
+	})
+
+	t.Run("pattern spans the canvas", func(t *testing.T) {
+		got := Canvas().Width(5).Height(1).BackgroundPattern(PatternGradient, nil).String()
+		assert.Equal(t, " ░▒▓█", got)
+	})
+
+	t.Run("transparent layers show the background", func(t *testing.T) {
+		got := Canvas().Background('.', nil).AddTransparent("a b", 0, 0).String()
+		assert.Equal(t, "a.b", got)
+	})
+
+	t.Run("styled background keeps trailing cells", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().Width(3).Height(1).Background(' ', Text().OnBlue()).Add("a", 0, 0).String()
+		assert.Equal(t, "\x1b[44ma  \x1b[0m", got)
+	})
+
+	t.Run("nested canvas with a background is opaque", func(t *testing.T) {
+		child := Canvas().Width(3).Height(1).Background('-', nil).Add("x", 1, 0)
+		got := Canvas().Add("abcd", 0, 0).AddCanvas(child, 0, 0).String()
+		assert.Equal(t, "-x-d", got)
+	})
+}
+
+func TestCanvasFillRect(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("fills a rectangle", func(t *testing.T) {
+		got := Canvas().Add("......\n......\n......", 0, 0).FillRect(1, 1, 3, 2, '#', nil).String()
+		assert.Equal(t, "......\n.###..\n.###..", got)
+	})
+
+	t.Run("empty rectangle", func(t *testing.T) {
+		assert.Equal(t, "", Canvas().FillRect(0, 0, 0, 2, '#', nil).String())
+	})
+
+	t.Run("styled fill", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().FillRect(0, 0, 2, 1, ' ', Text().OnRed()).Add("x", 0, 0).String()
+		assert.Equal(t, "\x1b[41mx \x1b[0m", got)
+	})
+}
+
+func TestCanvasBackgroundOnly(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	assert.Equal(t, "", Canvas().Background('.', nil).String())
+	assert.Equal(t, "..\n..", Canvas().Width(2).Height(2).Background('.', nil).String())
+	assert.Equal(t, "...", Canvas().Viewport(5, 5, 3, 1).Background('.', nil).String())
+}
@@ -0,0 +1,557 @@
+package tinta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Justify controls how a [FlexStyle] distributes leftover space along its
+// main axis once growing children have taken their share.
+type Justify int
+
+const (
+	// JustifyStart packs children at the start of the main axis.
+	JustifyStart Justify = iota
+	// JustifyEnd packs children at the end of the main axis.
+	JustifyEnd
+	// JustifyCenter packs children around the middle of the main axis.
+	JustifyCenter
+	// JustifySpaceBetween puts the first and last children at the edges and
+	// spreads the remaining space evenly between children.
+	JustifySpaceBetween
+	// JustifySpaceAround gives every child equal space on both sides, so the
+	// outer gaps are half the inner ones.
+	JustifySpaceAround
+	// JustifySpaceEvenly makes the outer gaps and the inner gaps equal.
+	JustifySpaceEvenly
+)
+
+type flexDirection int
+
+const (
+	flexRow flexDirection = iota
+	flexColumn
+)
+
+// flexNode is implemented by everything a flex container can hold.
+type flexNode interface {
+	flexFactors() (grow, shrink, basis int)
+	naturalSize() (w, h int)
+	heightFor(w int) int
+	renderBlock(w, h int) []string
+}
+
+// FlexItem is a leaf of a flex layout: plain text or content rendered in a
+// box. Create one with [Item] or [BoxItem] and set its flex factors with
+// Grow, Shrink and Basis. All methods return a new FlexItem.
+type FlexItem struct {
+	content string
+	box     *BoxStyle
+	grow    int
+	shrink  int
+	basis   int
+}
+
+// Item returns a [FlexItem] holding plain or pre-styled text. Text is
+// word-wrapped to the width the layout assigns.
+func Item(content string) *FlexItem {
+	return &FlexItem{content: content, shrink: 1}
+}
+
+// BoxItem returns a [FlexItem] that renders content inside b. The box is
+// rendered with the width the layout assigns, so its content wraps, and is
+// stretched to the assigned height when the container stretches children.
+func BoxItem(b *BoxStyle, content string) *FlexItem {
+	return &FlexItem{content: content, box: b, shrink: 1}
+}
+
+// Grow sets how much of the container's free space the item takes,
+// relative to its siblings. The default is 0 (no growing).
+func (it *FlexItem) Grow(n int) *FlexItem {
+	cp := *it
+	cp.grow = n
+	return &cp
+}
+
+// Shrink sets how much the item gives up when its siblings do not fit,
+// relative to its siblings and weighted by its basis. The default is 1;
+// 0 prevents shrinking.
+func (it *FlexItem) Shrink(n int) *FlexItem {
+	cp := *it
+	cp.shrink = n
+	return &cp
+}
+
+// Basis sets the main-axis size the item starts from before growing or
+// shrinking. Zero (default) uses the item's natural size.
+func (it *FlexItem) Basis(n int) *FlexItem {
+	cp := *it
+	cp.basis = n
+	return &cp
+}
+
+func (it *FlexItem) flexFactors() (grow, shrink, basis int) {
+	return it.grow, it.shrink, it.basis
+}
+
+func (it *FlexItem) naturalSize() (w, h int) {
+	lines := it.renderBlock(0, 0)
+	return blockWidth(lines), len(lines)
+}
+
+func (it *FlexItem) heightFor(w int) int {
+	return len(it.renderBlock(w, 0))
+}
+
+func (it *FlexItem) renderBlock(w, h int) []string {
+	if it.box != nil {
+		cp := copyBox(it.box)
+		if w > 0 {
+			cp.width = w
+			cp.widthPercent = 0
+		}
+		cp.minHeight = h
+		return strings.Split(cp.render(getOutput(), it.content), "\n")
+	}
+	lines := strings.Split(expandTabs(it.content, 0), "\n")
+	if w <= 0 {
+		return lines
+	}
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapLine(line, w)...)
+	}
+	return wrapped
+}
+
+// FlexStyle is a flexbox-style container that lays out its children in a
+// row or a column. Create one with [Row] or [Column], configure it with
+// Gap, Justify and AlignItems, and resolve it with [FlexStyle.Render].
+// A FlexStyle can itself be a child of another container, so it also has
+// Grow, Shrink and Basis. All methods return a new FlexStyle.
+type FlexStyle struct {
+	direction  flexDirection
+	children   []flexNode
+	gap        int
+	justify    Justify
+	alignItems Align
+	alignSet   bool
+	grow       int
+	shrink     int
+	basis      int
+}
+
+// Row returns a [FlexStyle] that lays out children from left to right.
+// Children may be strings, [FlexItem] values or nested containers; any
+// other value is formatted with fmt.Sprint.
+func Row(children ...any) *FlexStyle {
+	return newFlex(flexRow, children)
+}
+
+// Column returns a [FlexStyle] that lays out children from top to bottom.
+// Children may be strings, [FlexItem] values or nested containers; any
+// other value is formatted with fmt.Sprint.
+func Column(children ...any) *FlexStyle {
+	return newFlex(flexColumn, children)
+}
+
+func newFlex(dir flexDirection, children []any) *FlexStyle {
+	f := &FlexStyle{direction: dir, shrink: 1}
+	for _, c := range children {
+		f.children = append(f.children, toFlexNode(c))
+	}
+	return f
+}
+
+func toFlexNode(v any) flexNode {
+	switch c := v.(type) {
+	case *FlexStyle:
+		return c
+	case *FlexItem:
+		return c
+	case string:
+		return Item(c)
+	default:
+		return Item(fmt.Sprint(c))
+	}
+}
+
+func copyFlex(f *FlexStyle) *FlexStyle {
+	cp := *f
+	if len(f.children) > 0 {
+		cp.children = make([]flexNode, len(f.children))
+		copy(cp.children, f.children)
+	}
+	return &cp
+}
+
+// Gap sets the number of blank columns (in a row) or lines (in a column)
+// between adjacent children.
+func (f *FlexStyle) Gap(n int) *FlexStyle {
+	cp := copyFlex(f)
+	cp.gap = n
+	return cp
+}
+
+// Justify sets how leftover main-axis space is distributed. The default
+// is [JustifyStart].
+func (f *FlexStyle) Justify(j Justify) *FlexStyle {
+	cp := copyFlex(f)
+	cp.justify = j
+	return cp
+}
+
+// AlignItems sets how children are placed along the cross axis: a row
+// uses [AlignTop], [AlignMiddle] or [AlignBottom], a column uses
+// [AlignLeft], [AlignCenter] or [AlignRight]. When not set, children are
+// stretched to fill the cross axis.
+func (f *FlexStyle) AlignItems(a Align) *FlexStyle {
+	cp := copyFlex(f)
+	cp.alignItems = a
+	cp.alignSet = true
+	return cp
+}
+
+// Grow sets how much of its parent's free space the container takes.
+func (f *FlexStyle) Grow(n int) *FlexStyle {
+	cp := copyFlex(f)
+	cp.grow = n
+	return cp
+}
+
+// Shrink sets how much the container gives up when its siblings do not
+// fit. The default is 1; 0 prevents shrinking.
+func (f *FlexStyle) Shrink(n int) *FlexStyle {
+	cp := copyFlex(f)
+	cp.shrink = n
+	return cp
+}
+
+// Basis sets the main-axis size the container starts from inside its
+// parent. Zero (default) uses its natural size.
+func (f *FlexStyle) Basis(n int) *FlexStyle {
+	cp := copyFlex(f)
+	cp.basis = n
+	return cp
+}
+
+// Render resolves the layout against the given width and height and
+// returns the result. Every line of the output is exactly width columns
+// wide and there are exactly height lines; content that does not fit is
+// cropped. A width or height of zero or less uses the natural size of the
+// content along that axis.
+func (f *FlexStyle) Render(width, height int) string {
+	if width <= 0 {
+		width, _ = f.naturalSize()
+	}
+	if height <= 0 {
+		height = f.heightFor(width)
+	}
+	return strings.Join(f.renderBlock(width, height), "\n")
+}
+
+func (f *FlexStyle) flexFactors() (grow, shrink, basis int) {
+	return f.grow, f.shrink, f.basis
+}
+
+func (f *FlexStyle) gaps() int {
+	if len(f.children) < 2 || f.gap <= 0 {
+		return 0
+	}
+	return f.gap * (len(f.children) - 1)
+}
+
+func (f *FlexStyle) naturalSize() (w, h int) {
+	for _, c := range f.children {
+		cw, ch := c.naturalSize()
+		if _, _, basis := c.flexFactors(); basis > 0 {
+			if f.direction == flexRow {
+				cw = basis
+			} else {
+				ch = basis
+			}
+		}
+		if f.direction == flexRow {
+			w += cw
+			if ch > h {
+				h = ch
+			}
+		} else {
+			h += ch
+			if cw > w {
+				w = cw
+			}
+		}
+	}
+	if f.direction == flexRow {
+		w += f.gaps()
+	} else {
+		h += f.gaps()
+	}
+	return w, h
+}
+
+func (f *FlexStyle) heightFor(w int) int {
+	if f.direction == flexRow {
+		h := 0
+		for i, cw := range f.mainSizes(w, 0) {
+			if ch := f.children[i].heightFor(cw); ch > h {
+				h = ch
+			}
+		}
+		return h
+	}
+	h := f.gaps()
+	for _, base := range f.bases(w) {
+		h += base
+	}
+	return h
+}
+
+// crossWidth returns the width a column gives to child c.
+func (f *FlexStyle) crossWidth(c flexNode, w int) int {
+	if !f.alignSet {
+		return w
+	}
+	cw, _ := c.naturalSize()
+	if cw > w {
+		return w
+	}
+	return cw
+}
+
+// bases returns the main-axis size of every child before growing or
+// shrinking. cross is the container's width when laying out a column.
+func (f *FlexStyle) bases(cross int) []int {
+	bases := make([]int, len(f.children))
+	for i, c := range f.children {
+		if _, _, basis := c.flexFactors(); basis > 0 {
+			bases[i] = basis
+			continue
+		}
+		if f.direction == flexRow {
+			bases[i], _ = c.naturalSize()
+		} else {
+			bases[i] = c.heightFor(f.crossWidth(c, cross))
+		}
+	}
+	return bases
+}
+
+// mainSizes resolves the main-axis size of every child for a container
+// whose main axis is avail cells long.
+func (f *FlexStyle) mainSizes(avail, cross int) []int {
+	sizes := f.bases(cross)
+	free := avail - f.gaps()
+	for _, s := range sizes {
+		free -= s
+	}
+
+	switch {
+	case free > 0:
+		weights := make([]int, len(sizes))
+		for i, c := range f.children {
+			weights[i], _, _ = c.flexFactors()
+		}
+		for i, extra := range distribute(free, weights) {
+			sizes[i] += extra
+		}
+	case free < 0:
+		overflow := -free
+		for overflow > 0 {
+			weights := make([]int, len(sizes))
+			shrinkable := false
+			for i, c := range f.children {
+				_, shrink, _ := c.flexFactors()
+				if shrink > 0 && sizes[i] > 0 {
+					weights[i] = shrink * sizes[i]
+					shrinkable = true
+				}
+			}
+			if !shrinkable {
+				break
+			}
+			for i, cut := range distribute(overflow, weights) {
+				if cut > sizes[i] {
+					cut = sizes[i]
+				}
+				sizes[i] -= cut
+				overflow -= cut
+			}
+		}
+	}
+	return sizes
+}
+
+func (f *FlexStyle) renderBlock(w, h int) []string {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	if len(f.children) == 0 {
+		return fitBlock(nil, w, h, AlignLeft, AlignTop)
+	}
+	if f.direction == flexRow {
+		return f.renderRow(w, h)
+	}
+	return f.renderColumn(w, h)
+}
+
+func (f *FlexStyle) renderRow(w, h int) []string {
+	sizes := f.mainSizes(w, h)
+	spaces := f.spacing(w, sizes)
+
+	rows := make([]strings.Builder, h)
+	for i, c := range f.children {
+		for r := range rows {
+			rows[r].WriteString(strings.Repeat(" ", spaces[i]))
+		}
+		cw := sizes[i]
+		if cw <= 0 {
+			continue
+		}
+		ch := h
+		if f.alignSet {
+			if nh := c.heightFor(cw); nh < h {
+				ch = nh
+			}
+		}
+		block := fitBlock(c.renderBlock(cw, ch), cw, ch, AlignLeft, AlignTop)
+		offset := alignOffset(f.alignItems, h-ch)
+		blank := strings.Repeat(" ", cw)
+		for r := range rows {
+			if r >= offset && r-offset < len(block) {
+				rows[r].WriteString(block[r-offset])
+			} else {
+				rows[r].WriteString(blank)
+			}
+		}
+	}
+
+	lines := make([]string, h)
+	for r := range rows {
+		lines[r] = rows[r].String()
+	}
+	return fitBlock(lines, w, h, AlignLeft, AlignTop)
+}
+
+func (f *FlexStyle) renderColumn(w, h int) []string {
+	sizes := f.mainSizes(h, w)
+	spaces := f.spacing(h, sizes)
+	blank := strings.Repeat(" ", w)
+
+	var lines []string
+	for i, c := range f.children {
+		for s := 0; s < spaces[i]; s++ {
+			lines = append(lines, blank)
+		}
+		ch := sizes[i]
+		if ch <= 0 {
+			continue
+		}
+		cw := f.crossWidth(c, w)
+		block := fitBlock(c.renderBlock(cw, ch), cw, ch, AlignLeft, AlignTop)
+		lines = append(lines, fitBlock(block, w, ch, f.alignItems, AlignTop)...)
+	}
+	return fitBlock(lines, w, h, AlignLeft, AlignTop)
+}
+
+// spacing returns the blank cells placed before each child, gap included.
+// The trailing slot is implicit: the block is padded to its full size.
+func (f *FlexStyle) spacing(avail int, sizes []int) []int {
+	n := len(sizes)
+	free := avail - f.gaps()
+	for _, s := range sizes {
+		free -= s
+	}
+	if free < 0 {
+		free = 0
+	}
+
+	weights := make([]int, n+1)
+	switch f.justify {
+	case JustifyEnd:
+		weights[0] = 1
+	case JustifyCenter:
+		weights[0] = 1
+		weights[n] = 1
+	case JustifySpaceBetween:
+		if n == 1 {
+			weights[n] = 1
+		}
+		for i := 1; i < n; i++ {
+			weights[i] = 1
+		}
+	case JustifySpaceAround:
+		weights[0] = 1
+		weights[n] = 1
+		for i := 1; i < n; i++ {
+			weights[i] = 2
+		}
+	case JustifySpaceEvenly:
+		for i := range weights {
+			weights[i] = 1
+		}
+	default:
+		weights[n] = 1
+	}
+
+	spaces := distribute(free, weights)[:n]
+	if f.gap > 0 {
+		for i := 1; i < n; i++ {
+			spaces[i] += f.gap
+		}
+	}
+	return spaces
+}
+
+// distribute splits total across slots in proportion to weights. Any
+// remainder goes one cell at a time to the earliest weighted slots.
+func distribute(total int, weights []int) []int {
+	out := make([]int, len(weights))
+	sum := 0
+	for _, w := range weights {
+		if w > 0 {
+			sum += w
+		}
+	}
+	if total <= 0 || sum == 0 {
+		return out
+	}
+	given := 0
+	for i, w := range weights {
+		if w > 0 {
+			out[i] = total * w / sum
+			given += out[i]
+		}
+	}
+	for i := 0; given < total; i = (i + 1) % len(weights) {
+		if weights[i] > 0 {
+			out[i]++
+			given++
+		}
+	}
+	return out
+}
+
+// fitBlock pads or crops lines to exactly w columns and h rows. Extra
+// space is placed according to halign and valign.
+func fitBlock(lines []string, w, h int, halign, valign Align) []string {
+	if len(lines) > h {
+		lines = lines[:h]
+	}
+	out := make([]string, h)
+	blank := strings.Repeat(" ", w)
+	top := alignOffset(valign, h-len(lines))
+	for i := range out {
+		idx := i - top
+		if idx < 0 || idx >= len(lines) {
+			out[i] = blank
+			continue
+		}
+		line := truncateLine(lines[idx], w)
+		free := w - visibleWidth(line)
+		left := alignOffset(halign, free)
+		out[i] = strings.Repeat(" ", left) + line + strings.Repeat(" ", free-left)
+	}
+	return out
+}
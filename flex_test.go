@@ -0,0 +1,215 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestFlexRow(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("natural size", func(t *testing.T) {
+		got := Row("ab", "c").Render(0, 0)
+		assert.Equal(t, "abc", got)
+	})
+
+	t.Run("gap between children", func(t *testing.T) {
+		got := Row("ab", "c").Gap(2).Render(0, 0)
+		assert.Equal(t, "ab  c", got)
+	})
+
+	t.Run("leftover space is padded", func(t *testing.T) {
+		got := Row("ab", "c").Render(6, 1)
+		assert.Equal(t, "abc   ", got)
+	})
+
+	t.Run("grow takes free space", func(t *testing.T) {
+		got := Row(Item("a").Grow(1), "b").Render(5, 1)
+		assert.Equal(t, "a   b", got)
+	})
+
+	t.Run("grow is proportional", func(t *testing.T) {
+		got := Row(Item("a").Grow(1), Item("b").Grow(2), "|").Render(10, 1)
+		assert.Equal(t, "a   b    |", got)
+	})
+
+	t.Run("basis overrides natural width", func(t *testing.T) {
+		got := Row(Item("a").Basis(4), "b").Render(0, 0)
+		assert.Equal(t, "a   b", got)
+	})
+
+	t.Run("shrink wraps text", func(t *testing.T) {
+		got := Row(Item("aaa bbb"), Item("|").Shrink(0)).Render(4, 0)
+		assert.Equal(t, "aaa|\nbbb ", got)
+	})
+
+	t.Run("justify end", func(t *testing.T) {
+		got := Row("a", "b").Justify(JustifyEnd).Render(5, 1)
+		assert.Equal(t, "   ab", got)
+	})
+
+	t.Run("justify center", func(t *testing.T) {
+		got := Row("a", "b").Justify(JustifyCenter).Render(6, 1)
+		assert.Equal(t, "  ab  ", got)
+	})
+
+	t.Run("justify space between", func(t *testing.T) {
+		got := Row("a", "b", "c").Justify(JustifySpaceBetween).Render(7, 1)
+		assert.Equal(t, "a  b  c", got)
+	})
+
+	t.Run("justify space around", func(t *testing.T) {
+		got := Row("a", "b").Justify(JustifySpaceAround).Render(6, 1)
+		assert.Equal(t, " a  b ", got)
+	})
+
+	t.Run("justify space evenly", func(t *testing.T) {
+		got := Row("a", "b").Justify(JustifySpaceEvenly).Render(5, 1)
+		assert.Equal(t, " a b ", got)
+	})
+
+	t.Run("align items middle", func(t *testing.T) {
+		got := Row("a\nb\nc", "x").AlignItems(AlignMiddle).Render(0, 0)
+		assert.Equal(t, "a \nbx\nc ", got)
+	})
+
+	t.Run("align items bottom", func(t *testing.T) {
+		got := Row("a\nb\nc", "x").AlignItems(AlignBottom).Render(0, 0)
+		assert.Equal(t, "a \nb \ncx", got)
+	})
+
+	t.Run("boxes receive assigned width and stretch", func(t *testing.T) {
+		got := Row(
+			BoxItem(Box(), "left side").Grow(1),
+			BoxItem(Box(), "r\nr\nr").Shrink(0),
+		).Render(12, 0)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, 5, len(lines))
+		assert.Equal(t, "┌───────┐┌─┐", lines[0])
+		assert.Equal(t, "│left   ││r│", lines[1])
+		assert.Equal(t, "│side   ││r│", lines[2])
+		assert.Equal(t, "│       ││r│", lines[3])
+		assert.Equal(t, "└───────┘└─┘", lines[4])
+	})
+
+	t.Run("fixed height crops", func(t *testing.T) {
+		got := Row("a\nb\nc").Render(1, 2)
+		assert.Equal(t, "a\nb", got)
+	})
+
+	t.Run("overflow without shrink is cropped", func(t *testing.T) {
+		got := Row(Item("abc").Shrink(0), Item("def").Shrink(0)).Render(4, 1)
+		assert.Equal(t, "abcd", got)
+	})
+
+	t.Run("non-string children are formatted", func(t *testing.T) {
+		got := Row(42, " ", true).Render(0, 0)
+		assert.Equal(t, "42 true", got)
+	})
+}
+
+func TestFlexColumn(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("natural size", func(t *testing.T) {
+		got := Column("abc", "d").Render(0, 0)
+		assert.Equal(t, "abc\nd  ", got)
+	})
+
+	t.Run("gap inserts blank lines", func(t *testing.T) {
+		got := Column("a", "b").Gap(1).Render(0, 0)
+		assert.Equal(t, "a\n \nb", got)
+	})
+
+	t.Run("grow fills height", func(t *testing.T) {
+		got := Column(Item("a").Grow(1), "b").Render(1, 4)
+		assert.Equal(t, "a\n \n \nb", got)
+	})
+
+	t.Run("justify end", func(t *testing.T) {
+		got := Column("a", "b").Justify(JustifyEnd).Render(1, 4)
+		assert.Equal(t, " \n \na\nb", got)
+	})
+
+	t.Run("align items center", func(t *testing.T) {
+		got := Column("abcde", "x").AlignItems(AlignCenter).Render(0, 0)
+		assert.Equal(t, "abcde\n  x  ", got)
+	})
+
+	t.Run("align items right", func(t *testing.T) {
+		got := Column("abcd", "x").AlignItems(AlignRight).Render(0, 0)
+		assert.Equal(t, "abcd\n   x", got)
+	})
+
+	t.Run("stretched boxes span the width", func(t *testing.T) {
+		got := Column(BoxItem(Box(), "header"), BoxItem(Box(), "x")).Render(10, 0)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, 6, len(lines))
+		assert.Equal(t, "┌────────┐", lines[0])
+		assert.Equal(t, "┌────────┐", lines[3])
+		assert.Equal(t, "│x       │", lines[4])
+	})
+
+	t.Run("growing box stretches to the assigned height", func(t *testing.T) {
+		got := Column(BoxItem(Box(), "x").Grow(1), "footer").Render(6, 5)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌────┐", lines[0])
+		assert.Equal(t, "│x   │", lines[1])
+		assert.Equal(t, "│    │", lines[2])
+		assert.Equal(t, "└────┘", lines[3])
+		assert.Equal(t, "footer", lines[4])
+	})
+}
+
+func TestFlexNested(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("row inside column", func(t *testing.T) {
+		got := Column(
+			"title",
+			Row(Item("a").Grow(1), "b"),
+		).Render(5, 0)
+		assert.Equal(t, "title\na   b", got)
+	})
+
+	t.Run("column inside row", func(t *testing.T) {
+		got := Row(Column("a", "b"), " ", Column("c", "d")).Render(0, 0)
+		assert.Equal(t, "a c\nb d", got)
+	})
+
+	t.Run("nested container grows", func(t *testing.T) {
+		got := Row(Row("a", Item("b").Grow(1)).Grow(1), "|").Render(6, 1)
+		assert.Equal(t, "ab   |", got)
+	})
+}
+
+func TestFlexImmutability(t *testing.T) {
+	t.Run("container methods return copies", func(t *testing.T) {
+		base := Row("a", "b")
+		_ = base.Gap(3).Justify(JustifyEnd).AlignItems(AlignBottom).Grow(2)
+		assert.Equal(t, 0, base.gap)
+		assert.Equal(t, JustifyStart, base.justify)
+		assert.Equal(t, false, base.alignSet)
+		assert.Equal(t, 0, base.grow)
+	})
+
+	t.Run("item methods return copies", func(t *testing.T) {
+		base := Item("a")
+		_ = base.Grow(1).Shrink(0).Basis(3)
+		assert.Equal(t, 0, base.grow)
+		assert.Equal(t, 1, base.shrink)
+		assert.Equal(t, 0, base.basis)
+	})
+}
+
+func TestDistribute(t *testing.T) {
+	assert.Equal(t, []int{2, 1}, distribute(3, []int{1, 1}))
+	assert.Equal(t, []int{1, 0, 2}, distribute(3, []int{1, 0, 2}))
+	assert.Equal(t, []int{0, 0}, distribute(5, []int{0, 0}))
+	assert.Equal(t, []int{0, 0}, distribute(0, []int{1, 1}))
+}
@@ -0,0 +1,463 @@
+package tinta
+
+import (
+	"strconv"
+	"strings"
+)
+
+type trackKind int
+
+const (
+	trackAuto trackKind = iota
+	trackFixed
+	trackPercent
+	trackFraction
+)
+
+type track struct {
+	kind trackKind
+	n    int
+}
+
+// parseTrack reads a track size: "12" (cells), "30%" (of the grid size),
+// "2fr" (a share of the remaining space) or "auto" (natural content size).
+// Anything else is treated as "auto".
+func parseTrack(spec string) track {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case strings.HasSuffix(spec, "fr"):
+		n, err := strconv.Atoi(strings.TrimSuffix(spec, "fr"))
+		if err == nil && n > 0 {
+			return track{kind: trackFraction, n: n}
+		}
+	case strings.HasSuffix(spec, "%"):
+		n, err := strconv.Atoi(strings.TrimSuffix(spec, "%"))
+		if err == nil && n >= 0 {
+			return track{kind: trackPercent, n: n}
+		}
+	default:
+		n, err := strconv.Atoi(spec)
+		if err == nil && n >= 0 {
+			return track{kind: trackFixed, n: n}
+		}
+	}
+	return track{kind: trackAuto}
+}
+
+type gridCell struct {
+	row, col         int
+	rowSpan, colSpan int
+	node             flexNode
+}
+
+// GridStyle places content in a grid of rows and columns, similar to CSS
+// grid. Create one with [Grid], define tracks with Columns and Rows, place
+// content with Cell and CellSpan, and resolve it with [GridStyle.Render]
+// or [GridStyle.Canvas].
+//
+// All methods return a new GridStyle to preserve immutability.
+type GridStyle struct {
+	columns  []track
+	rows     []track
+	cells    []gridCell
+	gapX     int
+	gapY     int
+	border   Border
+	bordered bool
+}
+
+// Grid returns a new empty [GridStyle].
+func Grid() *GridStyle {
+	return &GridStyle{}
+}
+
+func copyGrid(g *GridStyle) *GridStyle {
+	cp := *g
+	if len(g.columns) > 0 {
+		cp.columns = make([]track, len(g.columns))
+		copy(cp.columns, g.columns)
+	}
+	if len(g.rows) > 0 {
+		cp.rows = make([]track, len(g.rows))
+		copy(cp.rows, g.rows)
+	}
+	if len(g.cells) > 0 {
+		cp.cells = make([]gridCell, len(g.cells))
+		copy(cp.cells, g.cells)
+	}
+	return &cp
+}
+
+func parseTracks(specs []string) []track {
+	tracks := make([]track, len(specs))
+	for i, spec := range specs {
+		tracks[i] = parseTrack(spec)
+	}
+	return tracks
+}
+
+// Columns defines the column tracks. Each size is a cell count ("20"), a
+// percentage of the grid width ("25%"), a fraction of the remaining width
+// ("1fr", "2fr") or "auto" for the widest single-column cell. Fractions
+// behave like "auto" when the grid is rendered at its natural width.
+// Columns used by cells beyond the defined tracks are "auto".
+func (g *GridStyle) Columns(sizes ...string) *GridStyle {
+	cp := copyGrid(g)
+	cp.columns = parseTracks(sizes)
+	return cp
+}
+
+// Rows defines the row tracks, using the same sizes as Columns measured
+// against the grid height. "auto" rows are as tall as their tallest
+// single-row cell at its resolved width.
+func (g *GridStyle) Rows(sizes ...string) *GridStyle {
+	cp := copyGrid(g)
+	cp.rows = parseTracks(sizes)
+	return cp
+}
+
+// Gap sets the gutter between both rows and columns.
+func (g *GridStyle) Gap(n int) *GridStyle {
+	cp := copyGrid(g)
+	cp.gapX = n
+	cp.gapY = n
+	return cp
+}
+
+// GapX sets the gutter between columns.
+func (g *GridStyle) GapX(n int) *GridStyle {
+	cp := copyGrid(g)
+	cp.gapX = n
+	return cp
+}
+
+// GapY sets the gutter between rows.
+func (g *GridStyle) GapY(n int) *GridStyle {
+	cp := copyGrid(g)
+	cp.gapY = n
+	return cp
+}
+
+// Border draws an outer frame and shared one-cell lines between all cells
+// using the glyphs of border, with junction glyphs where lines meet. Lines
+// are left out inside spanning cells. Gutters are ignored when a border
+// is set.
+func (g *GridStyle) Border(border Border) *GridStyle {
+	cp := copyGrid(g)
+	cp.border = border
+	cp.bordered = true
+	return cp
+}
+
+// Cell places content in the cell at row and col (both 0-based). Content
+// may be a string, a [FlexItem] or a [FlexStyle]; it is rendered into the
+// cell's rectangle, so boxes from [BoxItem] fill the whole cell.
+func (g *GridStyle) Cell(row, col int, content any) *GridStyle {
+	return g.CellSpan(row, col, 1, 1, content)
+}
+
+// CellSpan places content in a cell that starts at row and col and covers
+// rowSpan rows and colSpan columns. Spans below 1 are treated as 1.
+func (g *GridStyle) CellSpan(row, col, rowSpan, colSpan int, content any) *GridStyle {
+	if row < 0 || col < 0 {
+		return g
+	}
+	if rowSpan < 1 {
+		rowSpan = 1
+	}
+	if colSpan < 1 {
+		colSpan = 1
+	}
+	cp := copyGrid(g)
+	cp.cells = append(cp.cells, gridCell{
+		row:     row,
+		col:     col,
+		rowSpan: rowSpan,
+		colSpan: colSpan,
+		node:    toFlexNode(content),
+	})
+	return cp
+}
+
+// Render resolves the grid against the given width and height and returns
+// the composited result. A width or height of zero or less uses the
+// natural size of the content along that axis. Fixed and percent tracks
+// shrink when they leave no room for fractional ones; the grid only
+// exceeds the given size when auto tracks, borders and gutters do.
+func (g *GridStyle) Render(width, height int) string {
+	return g.Canvas(width, height).String()
+}
+
+// Canvas resolves the grid against the given width and height and returns
+// a [CanvasStyle] with one layer per cell, plus one for the border when
+// set. The canvas is sized to the grid, and further layers can be added
+// on top of it.
+func (g *GridStyle) Canvas(width, height int) *CanvasStyle {
+	nCols, nRows := len(g.columns), len(g.rows)
+	for _, c := range g.cells {
+		if c.col+c.colSpan > nCols {
+			nCols = c.col + c.colSpan
+		}
+		if c.row+c.rowSpan > nRows {
+			nRows = c.row + c.rowSpan
+		}
+	}
+	if nCols == 0 || nRows == 0 {
+		return Canvas()
+	}
+
+	colTracks := padTracks(g.columns, nCols)
+	rowTracks := padTracks(g.rows, nRows)
+
+	sepX, sepY := g.gapX, g.gapY
+	outer := 0
+	if g.bordered {
+		sepX, sepY, outer = 1, 1, 1
+	}
+	if sepX < 0 {
+		sepX = 0
+	}
+	if sepY < 0 {
+		sepY = 0
+	}
+
+	natW := make([]int, nCols)
+	for _, c := range g.cells {
+		if c.colSpan == 1 {
+			if w, _ := c.node.naturalSize(); w > natW[c.col] {
+				natW[c.col] = w
+			}
+		}
+	}
+	colW := resolveTracks(colTracks, natW, width, 2*outer+sepX*(nCols-1))
+
+	natH := make([]int, nRows)
+	for _, c := range g.cells {
+		if c.rowSpan == 1 {
+			if h := c.node.heightFor(spanSize(colW, c.col, c.colSpan, sepX)); h > natH[c.row] {
+				natH[c.row] = h
+			}
+		}
+	}
+	rowH := resolveTracks(rowTracks, natH, height, 2*outer+sepY*(nRows-1))
+
+	colX := trackOffsets(colW, outer, sepX)
+	rowY := trackOffsets(rowH, outer, sepY)
+	totalW := colX[nCols-1] + colW[nCols-1] + outer
+	totalH := rowY[nRows-1] + rowH[nRows-1] + outer
+
+	cv := Canvas().Width(totalW).Height(totalH)
+	if g.bordered {
+		cv = cv.Add(g.drawBorder(colW, rowH, nRows, nCols, totalW, totalH), 0, 0)
+	}
+	for _, c := range g.cells {
+		w := spanSize(colW, c.col, c.colSpan, sepX)
+		h := spanSize(rowH, c.row, c.rowSpan, sepY)
+		if w <= 0 || h <= 0 {
+			continue
+		}
+		block := fitBlock(c.node.renderBlock(w, h), w, h, AlignLeft, AlignTop)
+		cv = cv.Add(strings.Join(block, "\n"), colX[c.col], rowY[c.row])
+	}
+	return cv
+}
+
+func padTracks(tracks []track, n int) []track {
+	out := make([]track, n)
+	copy(out, tracks)
+	return out
+}
+
+// resolveTracks sizes tracks along an axis of total cells, of which
+// reserved go to borders and gutters. A total of zero or less sizes
+// every track that depends on it by its natural content size.
+//
+// Fraction tracks get at least one cell. When fixed and percent tracks
+// leave no room for that, they shrink in proportion to their size, down
+// to one cell each; only what still does not fit then overflows total.
+func resolveTracks(tracks []track, natural []int, total, reserved int) []int {
+	sizes := make([]int, len(tracks))
+	weights := make([]int, len(tracks))
+	fractions := 0
+	used := reserved
+	for i, t := range tracks {
+		switch {
+		case t.kind == trackFixed:
+			sizes[i] = t.n
+		case t.kind == trackPercent && total > 0:
+			sizes[i] = total * t.n / 100
+		case t.kind == trackFraction && total > 0:
+			weights[i] = t.n
+			fractions++
+			continue
+		default:
+			sizes[i] = natural[i]
+		}
+		used += sizes[i]
+	}
+	if total <= 0 {
+		return sizes
+	}
+
+	if over := used + fractions - total; over > 0 {
+		slack := make([]int, len(tracks))
+		sum := 0
+		for i, t := range tracks {
+			if (t.kind == trackFixed || t.kind == trackPercent) && sizes[i] > 1 {
+				slack[i] = sizes[i] - 1
+				sum += slack[i]
+			}
+		}
+		if over > sum {
+			over = sum
+		}
+		for i, cut := range distribute(over, slack) {
+			sizes[i] -= cut
+		}
+		used -= over
+	}
+
+	free := total - used
+	extra := distribute(free, weights)
+	for i, w := range weights {
+		if w > 0 && extra[i] == 0 {
+			// A small weight got nothing: give every fraction its cell
+			// first and share out the rest.
+			extra = distribute(free-fractions, weights)
+			for j, w := range weights {
+				if w > 0 {
+					extra[j]++
+				}
+			}
+			break
+		}
+	}
+	for i, n := range extra {
+		sizes[i] += n
+	}
+	return sizes
+}
+
+func trackOffsets(sizes []int, start, sep int) []int {
+	offsets := make([]int, len(sizes))
+	pos := start
+	for i, s := range sizes {
+		offsets[i] = pos
+		pos += s + sep
+	}
+	return offsets
+}
+
+// spanSize returns the size of span tracks starting at first, including
+// the separators between them.
+func spanSize(sizes []int, first, span, sep int) int {
+	n := 0
+	for i := first; i < first+span && i < len(sizes); i++ {
+		if i > first {
+			n += sep
+		}
+		n += sizes[i]
+	}
+	return n
+}
+
+// drawBorder renders the frame and shared cell lines of a bordered grid.
+func (g *GridStyle) drawBorder(colW, rowH []int, nRows, nCols, totalW, totalH int) string {
+	owner := make([][]int, nRows)
+	for r := range owner {
+		owner[r] = make([]int, nCols)
+		for c := range owner[r] {
+			owner[r][c] = -1
+		}
+	}
+	for i, c := range g.cells {
+		for r := c.row; r < c.row+c.rowSpan; r++ {
+			for col := c.col; col < c.col+c.colSpan; col++ {
+				owner[r][col] = i
+			}
+		}
+	}
+
+	// vert reports whether the line left of column c is drawn in row r.
+	vert := func(r, c int) bool {
+		if r < 0 || r >= nRows {
+			return false
+		}
+		if c == 0 || c == nCols {
+			return true
+		}
+		return owner[r][c-1] < 0 || owner[r][c-1] != owner[r][c]
+	}
+	// horiz reports whether the line above row r is drawn in column c.
+	horiz := func(r, c int) bool {
+		if c < 0 || c >= nCols {
+			return false
+		}
+		if r == 0 || r == nRows {
+			return true
+		}
+		return owner[r-1][c] < 0 || owner[r-1][c] != owner[r][c]
+	}
+
+	canvas := make([][]string, totalH)
+	for y := range canvas {
+		canvas[y] = make([]string, totalW)
+		for x := range canvas[y] {
+			canvas[y][x] = " "
+		}
+	}
+
+	bx := append(trackOffsets(colW, 0, 1), totalW-1)
+	by := append(trackOffsets(rowH, 0, 1), totalH-1)
+	b := g.border
+
+	for r := 0; r < nRows; r++ {
+		for c := 0; c <= nCols; c++ {
+			if !vert(r, c) {
+				continue
+			}
+			glyph := b.innerVertical()
+			switch c {
+			case 0:
+				glyph = b.Left
+			case nCols:
+				glyph = b.Right
+			}
+			for y := by[r] + 1; y < by[r+1]; y++ {
+				canvas[y][bx[c]] = glyph
+			}
+		}
+	}
+	for r := 0; r <= nRows; r++ {
+		for c := 0; c < nCols; c++ {
+			if !horiz(r, c) {
+				continue
+			}
+			glyph := b.innerHorizontal()
+			switch r {
+			case 0:
+				glyph = b.Top
+			case nRows:
+				glyph = b.Bottom
+			}
+			for x := bx[c] + 1; x < bx[c+1]; x++ {
+				canvas[by[r]][x] = glyph
+			}
+		}
+	}
+	for r := 0; r <= nRows; r++ {
+		for c := 0; c <= nCols; c++ {
+			lines := edgeSet(vert(r-1, c), vert(r, c), horiz(r, c-1), horiz(r, c))
+			frame := edgeSet(r == 0, r == nRows, c == 0, c == nCols)
+			if glyph := b.junction(lines, frame); glyph != "" {
+				canvas[by[r]][bx[c]] = glyph
+			}
+		}
+	}
+
+	lines := make([]string, totalH)
+	for y, row := range canvas {
+		lines[y] = strings.Join(row, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,193 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestGridTracks(t *testing.T) {
+	t.Run("parse track sizes", func(t *testing.T) {
+		assert.Equal(t, track{kind: trackFixed, n: 20}, parseTrack("20"))
+		assert.Equal(t, track{kind: trackFraction, n: 2}, parseTrack("2fr"))
+		assert.Equal(t, track{kind: trackPercent, n: 25}, parseTrack("25%"))
+		assert.Equal(t, track{kind: trackAuto}, parseTrack("auto"))
+		assert.Equal(t, track{kind: trackAuto}, parseTrack("wide"))
+	})
+
+	t.Run("fractions share the remaining width", func(t *testing.T) {
+		tracks := []track{{kind: trackFixed, n: 4}, {kind: trackFraction, n: 1}, {kind: trackFraction, n: 2}}
+		assert.Equal(t, []int{4, 2, 4}, resolveTracks(tracks, []int{0, 0, 0}, 12, 2))
+	})
+
+	t.Run("fractions fall back to natural size", func(t *testing.T) {
+		tracks := []track{{kind: trackFraction, n: 1}, {kind: trackAuto}}
+		assert.Equal(t, []int{3, 5}, resolveTracks(tracks, []int{3, 5}, 0, 0))
+	})
+
+	t.Run("fixed tracks shrink to leave fractions a cell", func(t *testing.T) {
+		tracks := []track{{kind: trackFixed, n: 20}, {kind: trackFraction, n: 1}}
+		assert.Equal(t, []int{7, 1}, resolveTracks(tracks, []int{0, 0}, 10, 2))
+	})
+
+	t.Run("fixed and percent tracks shrink in proportion", func(t *testing.T) {
+		tracks := []track{{kind: trackFixed, n: 9}, {kind: trackPercent, n: 50}, {kind: trackAuto}}
+		assert.Equal(t, []int{4, 4, 4}, resolveTracks(tracks, []int{0, 0, 4}, 12, 0))
+	})
+
+	t.Run("small fractions get a cell", func(t *testing.T) {
+		tracks := []track{{kind: trackFraction, n: 1}, {kind: trackFraction, n: 20}}
+		assert.Equal(t, []int{1, 9}, resolveTracks(tracks, []int{0, 0}, 10, 0))
+	})
+
+	t.Run("auto tracks overflow", func(t *testing.T) {
+		tracks := []track{{kind: trackAuto}, {kind: trackFixed, n: 4}, {kind: trackFraction, n: 1}}
+		assert.Equal(t, []int{12, 1, 1}, resolveTracks(tracks, []int{12, 0, 0}, 10, 0))
+	})
+
+	t.Run("percent of total", func(t *testing.T) {
+		tracks := []track{{kind: trackPercent, n: 50}, {kind: trackFraction, n: 1}}
+		assert.Equal(t, []int{10, 10}, resolveTracks(tracks, []int{0, 0}, 20, 0))
+	})
+}
+
+func TestGridRender(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("empty grid", func(t *testing.T) {
+		assert.Equal(t, "", Grid().Render(10, 5))
+	})
+
+	t.Run("natural size with gutters", func(t *testing.T) {
+		got := Grid().GapX(1).
+			Cell(0, 0, "ab").Cell(0, 1, "c").
+			Cell(1, 0, "d").Cell(1, 1, "ef").
+			Render(0, 0)
+		assert.Equal(t, "ab c\nd  ef", got)
+	})
+
+	t.Run("fixed and fraction columns", func(t *testing.T) {
+		got := Grid().Columns("3", "1fr", "2fr").
+			Cell(0, 0, "a").Cell(0, 1, "b").Cell(0, 2, "c").
+			Render(9, 0)
+		assert.Equal(t, "a  b c", got)
+	})
+
+	t.Run("oversized fixed column leaves the fraction room", func(t *testing.T) {
+		got := Grid().Columns("20", "1fr").Cell(0, 0, "left").Cell(0, 1, "right").
+			Border(BorderRounded).Render(10, 0)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, 10, visibleWidth(lines[0]))
+		assert.Equal(t, "╭──────┬─╮", lines[0])
+		assert.Equal(t, "│left  │r│", lines[1])
+	})
+
+	t.Run("row gutter", func(t *testing.T) {
+		got := Grid().GapY(1).Cell(0, 0, "a").Cell(1, 0, "b").Render(0, 0)
+		assert.Equal(t, "a\n\nb", got)
+	})
+
+	t.Run("fraction rows fill the height", func(t *testing.T) {
+		got := Grid().Rows("1fr", "1").Cell(0, 0, "a").Cell(1, 0, "b").Render(1, 4)
+		assert.Equal(t, "a\n\n\nb", got)
+	})
+
+	t.Run("content wraps to the column width", func(t *testing.T) {
+		got := Grid().Columns("3").Cell(0, 0, "aaa bbb").Render(0, 0)
+		assert.Equal(t, "aaa\nbbb", got)
+	})
+
+	t.Run("boxes fill their cell", func(t *testing.T) {
+		got := Grid().Columns("1fr", "1fr").Gap(1).
+			Cell(0, 0, BoxItem(Box(), "x")).
+			Cell(0, 1, BoxItem(Box(), "y\nz")).
+			Render(11, 0)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌───┐ ┌───┐", lines[0])
+		assert.Equal(t, "│x  │ │y  │", lines[1])
+		assert.Equal(t, "│   │ │z  │", lines[2])
+		assert.Equal(t, "└───┘ └───┘", lines[3])
+	})
+
+	t.Run("column span", func(t *testing.T) {
+		got := Grid().Columns("2", "2").
+			CellSpan(0, 0, 1, 2, "wide").
+			Cell(1, 0, "a").Cell(1, 1, "b").
+			Render(0, 0)
+		assert.Equal(t, "wide\na b", got)
+	})
+}
+
+func TestGridBorder(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("shared borders with junctions", func(t *testing.T) {
+		got := Grid().Border(BorderSimple).
+			Cell(0, 0, "a").Cell(0, 1, "b").
+			Cell(1, 0, "c").Cell(1, 1, "d").
+			Render(0, 0)
+		assert.Equal(t, "┌─┬─┐\n│a│b│\n├─┼─┤\n│c│d│\n└─┴─┘", got)
+	})
+
+	t.Run("spans remove inner lines", func(t *testing.T) {
+		got := Grid().Columns("5", "1fr", "2fr").Border(BorderSimple).
+			Cell(0, 0, "a").Cell(0, 1, "b").Cell(0, 2, "c").
+			CellSpan(1, 0, 1, 2, "wide").Cell(1, 2, "d").
+			CellSpan(2, 0, 1, 3, "all").
+			Render(20, 0)
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌─────┬────┬───────┐", lines[0])
+		assert.Equal(t, "│a    │b   │c      │", lines[1])
+		assert.Equal(t, "├─────┴────┼───────┤", lines[2])
+		assert.Equal(t, "│wide      │d      │", lines[3])
+		assert.Equal(t, "├──────────┴───────┤", lines[4])
+		assert.Equal(t, "│all               │", lines[5])
+		assert.Equal(t, "└──────────────────┘", lines[6])
+	})
+
+	t.Run("row span with double border", func(t *testing.T) {
+		got := Grid().Border(BorderDouble).
+			CellSpan(0, 0, 2, 1, "tall").
+			Cell(0, 1, "a").Cell(1, 1, "b").
+			Render(0, 0)
+		assert.Equal(t, "╔════╦═╗\n║tall║a║\n║    ╠═╣\n║    ║b║\n╚════╩═╝", got)
+	})
+
+	t.Run("ascii border uses plus junctions", func(t *testing.T) {
+		got := Grid().Border(BorderASCII).Cell(0, 0, "a").Cell(0, 1, "b").Render(0, 0)
+		assert.Equal(t, "+-+-+\n|a|b|\n+-+-+", got)
+	})
+}
+
+func TestGridCanvas(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("canvas accepts extra layers", func(t *testing.T) {
+		got := Grid().Columns("3").Cell(0, 0, "abc").Canvas(0, 0).Add("X", 1, 0).String()
+		assert.Equal(t, "aXc", got)
+	})
+}
+
+func TestGridImmutability(t *testing.T) {
+	base := Grid().Columns("1fr")
+	_ = base.Cell(0, 0, "a").Gap(2).Border(BorderSimple).Columns("1", "2")
+	assert.Equal(t, 0, len(base.cells))
+	assert.Equal(t, 0, base.gapX)
+	assert.Equal(t, false, base.bordered)
+	assert.Equal(t, 1, len(base.columns))
+}
+
+func TestGridMixedBorder(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	got := Grid().Border(BorderHeavySingle).
+		Cell(0, 0, "a").Cell(0, 1, "b").
+		CellSpan(1, 0, 1, 2, "cd").
+		Render(0, 0)
+	assert.Equal(t, "┏━┯━┓\n┃a│b┃\n┠─┴─┨\n┃cd ┃\n┗━━━┛", got)
+}
@@ -0,0 +1,193 @@
+package tinta
+
+import "strings"
+
+// JoinHorizontal places rendered blocks side by side, separated by gap
+// columns of spaces. Shorter blocks are padded with blank lines so that
+// all blocks share the height of the tallest one; align decides where
+// the padding goes: [AlignTop], [AlignMiddle] or [AlignBottom]. Every
+// block is padded to its own widest line, measured without ANSI escapes,
+// so the result is rectangular.
+func JoinHorizontal(align Align, gap int, blocks ...string) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	if gap < 0 {
+		gap = 0
+	}
+
+	split := make([][]string, len(blocks))
+	widths := make([]int, len(blocks))
+	height := 0
+	for i, block := range blocks {
+		split[i] = strings.Split(block, "\n")
+		widths[i] = blockWidth(split[i])
+		if len(split[i]) > height {
+			height = len(split[i])
+		}
+	}
+
+	rows := make([]strings.Builder, height)
+	sep := strings.Repeat(" ", gap)
+	for i, lines := range split {
+		offset := alignOffset(align, height-len(lines))
+		blank := strings.Repeat(" ", widths[i])
+		for row := 0; row < height; row++ {
+			if i > 0 {
+				rows[row].WriteString(sep)
+			}
+			idx := row - offset
+			if idx < 0 || idx >= len(lines) {
+				rows[row].WriteString(blank)
+				continue
+			}
+			rows[row].WriteString(lines[idx])
+			rows[row].WriteString(strings.Repeat(" ", widths[i]-visibleWidth(lines[idx])))
+		}
+	}
+
+	var out strings.Builder
+	for row := range rows {
+		if row > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(rows[row].String())
+	}
+	return out.String()
+}
+
+// JoinVertical stacks rendered blocks on top of each other, separated by
+// gap blank lines. Every line is padded to the width of the widest line
+// across all blocks, measured without ANSI escapes; align decides where
+// the padding goes: [AlignLeft], [AlignCenter] or [AlignRight].
+func JoinVertical(align Align, gap int, blocks ...string) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	if gap < 0 {
+		gap = 0
+	}
+
+	split := make([][]string, len(blocks))
+	width := 0
+	for i, block := range blocks {
+		split[i] = strings.Split(block, "\n")
+		if w := blockWidth(split[i]); w > width {
+			width = w
+		}
+	}
+
+	blank := strings.Repeat(" ", width)
+	var out strings.Builder
+	for i, lines := range split {
+		if i > 0 {
+			for g := 0; g < gap; g++ {
+				out.WriteByte('\n')
+				out.WriteString(blank)
+			}
+		}
+		for j, line := range lines {
+			if i > 0 || j > 0 {
+				out.WriteByte('\n')
+			}
+			free := width - visibleWidth(line)
+			left := alignOffset(align, free)
+			out.WriteString(strings.Repeat(" ", left))
+			out.WriteString(line)
+			out.WriteString(strings.Repeat(" ", free-left))
+		}
+	}
+	return out.String()
+}
+
+// CollapseHorizontal places bordered blocks side by side so that each
+// block's right border overlaps the next block's left border. The shared
+// edge is drawn once, with junctions where the frames meet, as with
+// [CanvasStyle.CollapseBorders]. Shorter blocks are placed according to
+// align, as in [JoinHorizontal].
+func CollapseHorizontal(align Align, blocks ...string) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	split := make([][]string, len(blocks))
+	height := 0
+	for i, block := range blocks {
+		split[i] = strings.Split(block, "\n")
+		if len(split[i]) > height {
+			height = len(split[i])
+		}
+	}
+
+	cv := Canvas().CollapseBorders()
+	x := 0
+	for i, lines := range split {
+		cv = cv.Add(blocks[i], x, alignOffset(align, height-len(lines)))
+		if w := blockWidth(lines); w > 0 {
+			x += w - 1
+		}
+	}
+	return padBlock(cv.Height(height).String())
+}
+
+// CollapseVertical stacks bordered blocks so that each block's bottom
+// border overlaps the next block's top border. The shared edge is drawn
+// once, with junctions where the frames meet, as with
+// [CanvasStyle.CollapseBorders]. Narrower blocks are placed according to
+// align, as in [JoinVertical].
+func CollapseVertical(align Align, blocks ...string) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	split := make([][]string, len(blocks))
+	width := 0
+	for i, block := range blocks {
+		split[i] = strings.Split(block, "\n")
+		if w := blockWidth(split[i]); w > width {
+			width = w
+		}
+	}
+
+	cv := Canvas().CollapseBorders()
+	y := 0
+	for i, lines := range split {
+		cv = cv.Add(blocks[i], alignOffset(align, width-blockWidth(lines)), y)
+		y += len(lines) - 1
+	}
+	return padBlock(cv.Width(width).String())
+}
+
+// padBlock pads every line of s with spaces to the width of its widest
+// line, undoing the trailing-space trimming done by the canvas.
+func padBlock(s string) string {
+	lines := strings.Split(s, "\n")
+	width := blockWidth(lines)
+	for i, line := range lines {
+		lines[i] = line + strings.Repeat(" ", width-visibleWidth(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func blockWidth(lines []string) int {
+	w := 0
+	for _, line := range lines {
+		if lw := visibleWidth(line); lw > w {
+			w = lw
+		}
+	}
+	return w
+}
+
+// alignOffset returns how much of free space goes before the content.
+func alignOffset(align Align, free int) int {
+	if free <= 0 {
+		return 0
+	}
+	switch align {
+	case AlignCenter:
+		return free / 2
+	case AlignRight:
+		return free
+	default:
+		return 0
+	}
+}
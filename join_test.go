@@ -0,0 +1,139 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestJoinHorizontal(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("no blocks", func(t *testing.T) {
+		assert.Equal(t, "", JoinHorizontal(AlignTop, 1))
+	})
+
+	t.Run("two boxes side by side", func(t *testing.T) {
+		a := Box().String("a")
+		b := Box().String("bb")
+		got := JoinHorizontal(AlignTop, 1, a, b)
+		assert.Equal(t, "┌─┐ ┌──┐\n│a│ │bb│\n└─┘ └──┘", got)
+	})
+
+	t.Run("top alignment pads below", func(t *testing.T) {
+		got := JoinHorizontal(AlignTop, 0, "a\nb\nc", "x")
+		assert.Equal(t, "ax\nb \nc ", got)
+	})
+
+	t.Run("middle alignment centers vertically", func(t *testing.T) {
+		got := JoinHorizontal(AlignMiddle, 0, "a\nb\nc", "x")
+		assert.Equal(t, "a \nbx\nc ", got)
+	})
+
+	t.Run("bottom alignment pads above", func(t *testing.T) {
+		got := JoinHorizontal(AlignBottom, 0, "a\nb\nc", "x")
+		assert.Equal(t, "a \nb \ncx", got)
+	})
+
+	t.Run("uneven lines are padded to block width", func(t *testing.T) {
+		got := JoinHorizontal(AlignTop, 1, "long\nx", "y")
+		assert.Equal(t, "long y\nx     ", got)
+	})
+
+	t.Run("ANSI-aware width", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		red := Text().Red().String("ab")
+		got := JoinHorizontal(AlignTop, 1, red+"\nc", "d")
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, red+" d", lines[0])
+		assert.Equal(t, "c   ", lines[1])
+	})
+
+	t.Run("negative gap is zero", func(t *testing.T) {
+		assert.Equal(t, "ab", JoinHorizontal(AlignTop, -2, "a", "b"))
+	})
+}
+
+func TestJoinVertical(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("no blocks", func(t *testing.T) {
+		assert.Equal(t, "", JoinVertical(AlignLeft, 1))
+	})
+
+	t.Run("left alignment", func(t *testing.T) {
+		got := JoinVertical(AlignLeft, 0, "abcd", "x")
+		assert.Equal(t, "abcd\nx   ", got)
+	})
+
+	t.Run("center alignment", func(t *testing.T) {
+		got := JoinVertical(AlignCenter, 0, "abcde", "x")
+		assert.Equal(t, "abcde\n  x  ", got)
+	})
+
+	t.Run("right alignment", func(t *testing.T) {
+		got := JoinVertical(AlignRight, 0, "abcd", "x")
+		assert.Equal(t, "abcd\n   x", got)
+	})
+
+	t.Run("gap inserts blank lines", func(t *testing.T) {
+		got := JoinVertical(AlignLeft, 2, "ab", "c")
+		assert.Equal(t, "ab\n  \n  \nc ", got)
+	})
+
+	t.Run("boxes stacked and centered", func(t *testing.T) {
+		got := JoinVertical(AlignCenter, 0, Box().String("title"), Box().String("x"))
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, 6, len(lines))
+		assert.Equal(t, "  ┌─┐  ", lines[3])
+		assert.Equal(t, "  │x│  ", lines[4])
+	})
+}
+
+func TestCollapse(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("no blocks", func(t *testing.T) {
+		assert.Equal(t, "", CollapseHorizontal(AlignTop))
+		assert.Equal(t, "", CollapseVertical(AlignLeft))
+	})
+
+	t.Run("horizontal shares the edge", func(t *testing.T) {
+		a := Box().String("cpu")
+		b := Box().String("mem")
+		got := CollapseHorizontal(AlignTop, a, b)
+		assert.Equal(t, "┌───┬───┐\n│cpu│mem│\n└───┴───┘", got)
+	})
+
+	t.Run("vertical shares the edge", func(t *testing.T) {
+		a := Box().String("cpu")
+		b := Box().String("mem")
+		got := CollapseVertical(AlignLeft, a, b)
+		assert.Equal(t, "┌───┐\n│cpu│\n├───┤\n│mem│\n└───┘", got)
+	})
+
+	t.Run("shorter block ends on a tee", func(t *testing.T) {
+		a := Box().String("a\nb")
+		b := Box().String("x")
+		got := CollapseHorizontal(AlignTop, a, b)
+		assert.Equal(t, "┌─┬─┐\n│a│x│\n│b├─┘\n└─┘  ", got)
+	})
+
+	t.Run("dashboard of rows", func(t *testing.T) {
+		top := CollapseHorizontal(AlignTop, Box().String("a"), Box().String("b"))
+		bottom := Box().String("ccc")
+		got := CollapseVertical(AlignLeft, top, bottom)
+		assert.Equal(t, "┌─┬─┐\n│a│b│\n├─┴─┤\n│ccc│\n└───┘", got)
+	})
+
+	t.Run("rounded corners merge", func(t *testing.T) {
+		a := Box().Border(BorderRounded).String("a")
+		got := CollapseHorizontal(AlignTop, a, a)
+		assert.Equal(t, "╭─┬─╮\n│a│a│\n╰─┴─╯", got)
+	})
+}
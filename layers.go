@@ -0,0 +1,120 @@
+package tinta
+
+// AddNamed places a rendered string at (x, y) like [CanvasStyle.Add] and
+// names the layer id. Named layers can be updated with [CanvasStyle.Move],
+// [CanvasStyle.Replace], [CanvasStyle.SetZ], [CanvasStyle.Hide],
+// [CanvasStyle.Show] and [CanvasStyle.Remove], and other layers can be
+// placed relative to them.
+func (c *CanvasStyle) AddNamed(id, s string, x, y int) *CanvasStyle {
+	return c.addLayer(layer{id: id, grid: c.parse(s), x: x, y: y, z: c.nextZ})
+}
+
+// Name names the layers added by the previous call, such as
+// [CanvasStyle.AddTransparent] or [CanvasStyle.AddShadow], so that they
+// can be updated like the ones added with [CanvasStyle.AddNamed]. A layer
+// added with a shadow is named together with its shadow.
+func (c *CanvasStyle) Name(id string) *CanvasStyle {
+	cp := copyCanvas(c)
+	for i := cp.lastAdd; i < len(cp.layers); i++ {
+		cp.layers[i].id = id
+	}
+	return cp
+}
+
+// Move places the layers named id at (x, y). An anchored layer becomes
+// absolutely positioned. Layers named together, such as a layer and its
+// shadow, move by the same amount as the last of them.
+func (c *CanvasStyle) Move(id string, x, y int) *CanvasStyle {
+	layers := c.resolve()
+	i := lastNamed(layers, id)
+	if i < 0 {
+		return c
+	}
+	dx, dy := x-layers[i].x, y-layers[i].y
+	cp := copyCanvas(c)
+	for j, ly := range layers {
+		if ly.id == id {
+			cp.layers[j].x, cp.layers[j].y = ly.x+dx, ly.y+dy
+			cp.layers[j].anchor = nil
+		}
+	}
+	return cp
+}
+
+// Replace swaps the content of the layers named id for s, keeping their
+// position, z-index and options such as transparency, blending or
+// transforms. A line layer becomes a plain layer.
+func (c *CanvasStyle) Replace(id, s string) *CanvasStyle {
+	return c.update(id, func(ly *layer) {
+		ly.grid = c.parse(s)
+		if ly.prepare != nil {
+			ly.prepare(ly.grid)
+		}
+		ly.edges = nil
+		ly.rounded = false
+		for _, t := range ly.transforms {
+			ly.grid, _ = applyTransform(t, ly.grid, nil)
+		}
+	})
+}
+
+// Remove deletes the layers named id.
+func (c *CanvasStyle) Remove(id string) *CanvasStyle {
+	cp := copyCanvas(c)
+	kept := cp.layers[:0]
+	for _, ly := range cp.layers {
+		if ly.id != id {
+			kept = append(kept, ly)
+		}
+	}
+	cp.layers = kept
+	cp.lastAdd = len(kept)
+	return cp
+}
+
+// SetZ changes the z-index of the layers named id. Layers sharing a z
+// keep their insertion order.
+func (c *CanvasStyle) SetZ(id string, z int) *CanvasStyle {
+	cp := c.update(id, func(ly *layer) { ly.z = z })
+	if z >= cp.nextZ {
+		cp.nextZ = z + 1
+	}
+	return cp
+}
+
+// Hide stops drawing the layers named id without removing them. Hidden
+// layers do not count toward the auto-sized canvas, but layers placed
+// relative to them keep their position.
+func (c *CanvasStyle) Hide(id string) *CanvasStyle {
+	return c.update(id, func(ly *layer) { ly.hidden = true })
+}
+
+// Show draws the layers named id again after [CanvasStyle.Hide].
+func (c *CanvasStyle) Show(id string) *CanvasStyle {
+	return c.update(id, func(ly *layer) { ly.hidden = false })
+}
+
+// Bounds returns the position and size of the last layer named id, with
+// anchors resolved, in the coordinates used by [CanvasStyle.Add]. ok is
+// false when no layer has that name.
+func (c *CanvasStyle) Bounds(id string) (x, y, w, h int, ok bool) {
+	layers := c.resolve()
+	i := lastNamed(layers, id)
+	if i < 0 {
+		return 0, 0, 0, 0, false
+	}
+	w, h = layers[i].size()
+	return layers[i].x, layers[i].y, w, h, true
+}
+
+// update returns a copy of the canvas with fn applied to every layer
+// named id.
+func (c *CanvasStyle) update(id string, fn func(*layer)) *CanvasStyle {
+	cp := copyCanvas(c)
+	for i := range cp.layers {
+		if cp.layers[i].id == id {
+			fn(&cp.layers[i])
+		}
+	}
+	return cp
+}
@@ -0,0 +1,89 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasNamedLayers(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	base := Canvas().Add("......", 0, 0).AddNamed("p", "ab", 1, 0)
+
+	t.Run("move", func(t *testing.T) {
+		assert.Equal(t, "....ab", base.Move("p", 4, 0).String())
+		assert.Equal(t, ".ab...", base.String())
+	})
+
+	t.Run("move anchored layer", func(t *testing.T) {
+		c := base.PlaceNamed("q", "x", AnchorCenter, 0, 0).Move("q", 0, 0)
+		assert.Equal(t, "xab...", c.String())
+	})
+
+	t.Run("replace keeps position", func(t *testing.T) {
+		assert.Equal(t, ".xyz..", base.Replace("p", "xyz").String())
+	})
+
+	t.Run("name the previous layer", func(t *testing.T) {
+		c := Canvas().Add("......", 0, 0).AddTransparent("a b", 1, 0).Name("t")
+		assert.Equal(t, ".a.b..", c.String())
+		assert.Equal(t, ".x.y..", c.Replace("t", "x y").String())
+		assert.Equal(t, "...a.b", c.Move("t", 3, 0).String())
+	})
+
+	t.Run("replace keeps the transparent rune", func(t *testing.T) {
+		c := Canvas().Add("......", 0, 0).AddTransparentRune("ab", 1, 0, '-').Name("t")
+		assert.Equal(t, ".x.y..", c.Replace("t", "x-y").String())
+	})
+
+	t.Run("shadow moves and reshapes with its layer", func(t *testing.T) {
+		c := Canvas().AddShadow("ab", 0, 0, 1, 1, ShadowDark).Name("s")
+		assert.Equal(t, "ab\n ▓▓", c.String())
+		assert.Equal(t, "  ab\n   ▓▓", c.Move("s", 2, 0).String())
+		assert.Equal(t, "a\n ▓", c.Replace("s", "a").String())
+		assert.Equal(t, "", c.Remove("s").String())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.Equal(t, "......", base.Remove("p").String())
+		assert.Equal(t, "...x..", base.Remove("p").Add("x", 3, 0).String())
+	})
+
+	t.Run("set z", func(t *testing.T) {
+		c := Canvas().AddNamed("low", "aaa", 0, 0).AddNamed("high", "b", 1, 0)
+		assert.Equal(t, "aba", c.String())
+		assert.Equal(t, "aaa", c.SetZ("low", 5).String())
+		assert.Equal(t, "aac", c.SetZ("low", 5).Add("c", 2, 0).String())
+	})
+
+	t.Run("hide and show", func(t *testing.T) {
+		hidden := base.Hide("p")
+		assert.Equal(t, "......", hidden.String())
+		assert.Equal(t, ".ab...", hidden.Show("p").String())
+		assert.Equal(t, "", Canvas().AddNamed("p", "abc", 0, 0).Hide("p").String())
+	})
+
+	t.Run("hidden layer keeps relative positions", func(t *testing.T) {
+		c := Canvas().AddNamed("a", "aa", 0, 0).Place("b", RightOf("a", 0), 0, 0).Hide("a")
+		assert.Equal(t, "  b", c.String())
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		x, y, w, h, ok := base.Bounds("p")
+		assert.Equal(t, []int{1, 0, 2, 1}, []int{x, y, w, h})
+		assert.Equal(t, true, ok)
+
+		c := Canvas().Width(10).Height(5).PlaceNamed("box", Box().String("hi"), AnchorCenter, 0, 0)
+		x, y, w, h, _ = c.Bounds("box")
+		assert.Equal(t, []int{3, 1, 4, 3}, []int{x, y, w, h})
+
+		_, _, _, _, ok = base.Bounds("missing")
+		assert.Equal(t, false, ok)
+	})
+
+	t.Run("unknown id leaves the canvas unchanged", func(t *testing.T) {
+		assert.Equal(t, base.String(), base.Move("x", 3, 3).Replace("x", "zz").Hide("x").String())
+	})
+}
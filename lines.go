@@ -0,0 +1,412 @@
+package tinta
+
+// LineWeight selects the box-drawing glyphs used by the canvas line
+// methods: [CanvasStyle.HLine], [CanvasStyle.VLine], [CanvasStyle.Rect]
+// and [CanvasStyle.Line].
+type LineWeight int
+
+const (
+	LineLight   LineWeight = iota // ─ │ ┌ ┼
+	LineHeavy                     // ━ ┃ ┏ ╋
+	LineDouble                    // ═ ║ ╔ ╬
+	LineRounded                   // ─ │ ╭ ┼, light lines with rounded corners
+)
+
+func (w LineWeight) arm() lineWeight {
+	switch w {
+	case LineHeavy:
+		return lineHeavy
+	case LineDouble:
+		return lineDouble
+	default:
+		return lineLight
+	}
+}
+
+// lineWeight is the weight of one arm of a box-drawing glyph.
+type lineWeight uint8
+
+const (
+	lineLight lineWeight = iota + 1
+	lineHeavy
+	lineDouble
+)
+
+// lineEdges holds the weight of the four arms of a box-drawing glyph, in
+// the order up, down, left, right, so that i^1 is the arm opposite i. A
+// zero weight means no arm.
+type lineEdges [4]lineWeight
+
+const (
+	armUp = iota
+	armDown
+	armLeft
+	armRight
+)
+
+// lineGlyphs maps every canonical box-drawing glyph to its arms. Each
+// combination of arms appears once, so the table can be inverted.
+var lineGlyphs = map[rune]lineEdges{
+	'─': {0, 0, lineLight, lineLight},
+	'━': {0, 0, lineHeavy, lineHeavy},
+	'│': {lineLight, lineLight, 0, 0},
+	'┃': {lineHeavy, lineHeavy, 0, 0},
+	'┌': {0, lineLight, 0, lineLight},
+	'┍': {0, lineLight, 0, lineHeavy},
+	'┎': {0, lineHeavy, 0, lineLight},
+	'┏': {0, lineHeavy, 0, lineHeavy},
+	'┐': {0, lineLight, lineLight, 0},
+	'┑': {0, lineLight, lineHeavy, 0},
+	'┒': {0, lineHeavy, lineLight, 0},
+	'┓': {0, lineHeavy, lineHeavy, 0},
+	'└': {lineLight, 0, 0, lineLight},
+	'┕': {lineLight, 0, 0, lineHeavy},
+	'┖': {lineHeavy, 0, 0, lineLight},
+	'┗': {lineHeavy, 0, 0, lineHeavy},
+	'┘': {lineLight, 0, lineLight, 0},
+	'┙': {lineLight, 0, lineHeavy, 0},
+	'┚': {lineHeavy, 0, lineLight, 0},
+	'┛': {lineHeavy, 0, lineHeavy, 0},
+	'├': {lineLight, lineLight, 0, lineLight},
+	'┝': {lineLight, lineLight, 0, lineHeavy},
+	'┞': {lineHeavy, lineLight, 0, lineLight},
+	'┟': {lineLight, lineHeavy, 0, lineLight},
+	'┠': {lineHeavy, lineHeavy, 0, lineLight},
+	'┡': {lineHeavy, lineLight, 0, lineHeavy},
+	'┢': {lineLight, lineHeavy, 0, lineHeavy},
+	'┣': {lineHeavy, lineHeavy, 0, lineHeavy},
+	'┤': {lineLight, lineLight, lineLight, 0},
+	'┥': {lineLight, lineLight, lineHeavy, 0},
+	'┦': {lineHeavy, lineLight, lineLight, 0},
+	'┧': {lineLight, lineHeavy, lineLight, 0},
+	'┨': {lineHeavy, lineHeavy, lineLight, 0},
+	'┩': {lineHeavy, lineLight, lineHeavy, 0},
+	'┪': {lineLight, lineHeavy, lineHeavy, 0},
+	'┫': {lineHeavy, lineHeavy, lineHeavy, 0},
+	'┬': {0, lineLight, lineLight, lineLight},
+	'┭': {0, lineLight, lineHeavy, lineLight},
+	'┮': {0, lineLight, lineLight, lineHeavy},
+	'┯': {0, lineLight, lineHeavy, lineHeavy},
+	'┰': {0, lineHeavy, lineLight, lineLight},
+	'┱': {0, lineHeavy, lineHeavy, lineLight},
+	'┲': {0, lineHeavy, lineLight, lineHeavy},
+	'┳': {0, lineHeavy, lineHeavy, lineHeavy},
+	'┴': {lineLight, 0, lineLight, lineLight},
+	'┵': {lineLight, 0, lineHeavy, lineLight},
+	'┶': {lineLight, 0, lineLight, lineHeavy},
+	'┷': {lineLight, 0, lineHeavy, lineHeavy},
+	'┸': {lineHeavy, 0, lineLight, lineLight},
+	'┹': {lineHeavy, 0, lineHeavy, lineLight},
+	'┺': {lineHeavy, 0, lineLight, lineHeavy},
+	'┻': {lineHeavy, 0, lineHeavy, lineHeavy},
+	'┼': {lineLight, lineLight, lineLight, lineLight},
+	'┽': {lineLight, lineLight, lineHeavy, lineLight},
+	'┾': {lineLight, lineLight, lineLight, lineHeavy},
+	'┿': {lineLight, lineLight, lineHeavy, lineHeavy},
+	'╀': {lineHeavy, lineLight, lineLight, lineLight},
+	'╁': {lineLight, lineHeavy, lineLight, lineLight},
+	'╂': {lineHeavy, lineHeavy, lineLight, lineLight},
+	'╃': {lineHeavy, lineLight, lineHeavy, lineLight},
+	'╄': {lineHeavy, lineLight, lineLight, lineHeavy},
+	'╅': {lineLight, lineHeavy, lineHeavy, lineLight},
+	'╆': {lineLight, lineHeavy, lineLight, lineHeavy},
+	'╇': {lineHeavy, lineLight, lineHeavy, lineHeavy},
+	'╈': {lineLight, lineHeavy, lineHeavy, lineHeavy},
+	'╉': {lineHeavy, lineHeavy, lineHeavy, lineLight},
+	'╊': {lineHeavy, lineHeavy, lineLight, lineHeavy},
+	'╋': {lineHeavy, lineHeavy, lineHeavy, lineHeavy},
+	'═': {0, 0, lineDouble, lineDouble},
+	'║': {lineDouble, lineDouble, 0, 0},
+	'╒': {0, lineLight, 0, lineDouble},
+	'╓': {0, lineDouble, 0, lineLight},
+	'╔': {0, lineDouble, 0, lineDouble},
+	'╕': {0, lineLight, lineDouble, 0},
+	'╖': {0, lineDouble, lineLight, 0},
+	'╗': {0, lineDouble, lineDouble, 0},
+	'╘': {lineLight, 0, 0, lineDouble},
+	'╙': {lineDouble, 0, 0, lineLight},
+	'╚': {lineDouble, 0, 0, lineDouble},
+	'╛': {lineLight, 0, lineDouble, 0},
+	'╜': {lineDouble, 0, lineLight, 0},
+	'╝': {lineDouble, 0, lineDouble, 0},
+	'╞': {lineLight, lineLight, 0, lineDouble},
+	'╟': {lineDouble, lineDouble, 0, lineLight},
+	'╠': {lineDouble, lineDouble, 0, lineDouble},
+	'╡': {lineLight, lineLight, lineDouble, 0},
+	'╢': {lineDouble, lineDouble, lineLight, 0},
+	'╣': {lineDouble, lineDouble, lineDouble, 0},
+	'╤': {0, lineLight, lineDouble, lineDouble},
+	'╥': {0, lineDouble, lineLight, lineLight},
+	'╦': {0, lineDouble, lineDouble, lineDouble},
+	'╧': {lineLight, 0, lineDouble, lineDouble},
+	'╨': {lineDouble, 0, lineLight, lineLight},
+	'╩': {lineDouble, 0, lineDouble, lineDouble},
+	'╪': {lineLight, lineLight, lineDouble, lineDouble},
+	'╫': {lineDouble, lineDouble, lineLight, lineLight},
+	'╬': {lineDouble, lineDouble, lineDouble, lineDouble},
+	'╴': {0, 0, lineLight, 0},
+	'╵': {lineLight, 0, 0, 0},
+	'╶': {0, 0, 0, lineLight},
+	'╷': {0, lineLight, 0, 0},
+	'╸': {0, 0, lineHeavy, 0},
+	'╹': {lineHeavy, 0, 0, 0},
+	'╺': {0, 0, 0, lineHeavy},
+	'╻': {0, lineHeavy, 0, 0},
+	'╼': {0, 0, lineLight, lineHeavy},
+	'╽': {lineLight, lineHeavy, 0, 0},
+	'╾': {0, 0, lineHeavy, lineLight},
+	'╿': {lineHeavy, lineLight, 0, 0},
+}
+
+// lineAliases maps dashed and rounded glyphs to the arms of their solid,
+// square equivalents. They are recognized when merging but never produced.
+var lineAliases = map[rune]lineEdges{
+	'┄': {0, 0, lineLight, lineLight},
+	'┅': {0, 0, lineHeavy, lineHeavy},
+	'┆': {lineLight, lineLight, 0, 0},
+	'┇': {lineHeavy, lineHeavy, 0, 0},
+	'┈': {0, 0, lineLight, lineLight},
+	'┉': {0, 0, lineHeavy, lineHeavy},
+	'┊': {lineLight, lineLight, 0, 0},
+	'┋': {lineHeavy, lineHeavy, 0, 0},
+	'╌': {0, 0, lineLight, lineLight},
+	'╍': {0, 0, lineHeavy, lineHeavy},
+	'╎': {lineLight, lineLight, 0, 0},
+	'╏': {lineHeavy, lineHeavy, 0, 0},
+	'╭': {0, lineLight, 0, lineLight},
+	'╮': {0, lineLight, lineLight, 0},
+	'╯': {lineLight, 0, lineLight, 0},
+	'╰': {lineLight, 0, 0, lineLight},
+}
+
+var glyphsByEdges = func() map[lineEdges]rune {
+	m := make(map[lineEdges]rune, len(lineGlyphs))
+	for r, e := range lineGlyphs {
+		m[e] = r
+	}
+	return m
+}()
+
+// edgesOf returns the arms of a box-drawing glyph.
+func edgesOf(r rune) (lineEdges, bool) {
+	if e, ok := lineGlyphs[r]; ok {
+		return e, true
+	}
+	e, ok := lineAliases[r]
+	return e, ok
+}
+
+// glyphFor returns the glyph with the given arms. Unicode has no glyph for
+// some mixes of heavy and double arms; those degrade to light arms, first
+// the heavy ones, then the double ones, then all of them.
+func glyphFor(e lineEdges) (rune, bool) {
+	if r, ok := glyphsByEdges[e]; ok {
+		return r, true
+	}
+	for _, from := range []lineWeight{lineHeavy, lineDouble} {
+		for i := range e {
+			if e[i] == from {
+				e[i] = lineLight
+			}
+		}
+		if r, ok := glyphsByEdges[e]; ok {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// mergeLines combines a box-drawing glyph drawn on top of another into the
+// glyph that has the arms of both, so that crossing or touching lines form
+// junctions. Arms of the upper glyph win over the lower one. When either
+// rune is not a box-drawing glyph, the upper rune is returned unchanged.
+func mergeLines(below, above rune) rune {
+	be, ok := edgesOf(below)
+	if !ok {
+		return above
+	}
+	ae, ok := edgesOf(above)
+	if !ok {
+		return above
+	}
+	merged := ae
+	for i, w := range merged {
+		if w == 0 {
+			merged[i] = be[i]
+		}
+	}
+	if merged == ae {
+		return above
+	}
+	if r, ok := glyphsByEdges[merged]; ok {
+		return r
+	}
+	// Unicode only mixes weights between the vertical and the horizontal
+	// pair, so an arm borrowed from below takes the weight of the upper
+	// glyph's opposite arm before falling back to light arms.
+	for i, w := range ae {
+		if w == 0 && merged[i] != 0 && ae[i^1] != 0 {
+			merged[i] = ae[i^1]
+		}
+	}
+	if r, ok := glyphFor(merged); ok {
+		return r
+	}
+	return above
+}
+
+// roundedCorners maps light corners to their rounded forms.
+var roundedCorners = map[rune]rune{'┌': '╭', '┐': '╮', '┘': '╯', '└': '╰'}
+
+// lineGlyph returns the glyph drawn for a cell with the given arms. A cell
+// with a single arm is the end of a line and is drawn as a full straight
+// segment; its arms stay as they are so that a later line meeting it forms
+// a tee rather than a cross.
+func lineGlyph(e lineEdges, rounded bool) rune {
+	n, last := 0, 0
+	for i, w := range e {
+		if w != 0 {
+			n++
+			last = i
+		}
+	}
+	if n == 1 {
+		e[last^1] = e[last]
+	}
+	r, ok := glyphFor(e)
+	if !ok {
+		return ' '
+	}
+	if rounded {
+		if rc, ok := roundedCorners[r]; ok {
+			return rc
+		}
+	}
+	return r
+}
+
+// HLine draws a horizontal line of length cells starting at (x, y) and
+// extending to the right. Where it meets other lines drawn with the line
+// methods, or box-drawing glyphs already on the canvas, the cell gets the
+// matching junction glyph.
+func (c *CanvasStyle) HLine(x, y, length int, weight LineWeight) *CanvasStyle {
+	if length < 1 {
+		return c
+	}
+	return c.addPath(weight, [2]int{x, y}, [2]int{x + length - 1, y})
+}
+
+// VLine draws a vertical line of length cells starting at (x, y) and
+// extending down. Junctions are merged as in [CanvasStyle.HLine].
+func (c *CanvasStyle) VLine(x, y, length int, weight LineWeight) *CanvasStyle {
+	if length < 1 {
+		return c
+	}
+	return c.addPath(weight, [2]int{x, y}, [2]int{x, y + length - 1})
+}
+
+// Rect draws the outline of a w x h rectangle whose top-left corner is at
+// (x, y). Junctions are merged as in [CanvasStyle.HLine], so rectangles
+// sharing an edge draw it once.
+func (c *CanvasStyle) Rect(x, y, w, h int, weight LineWeight) *CanvasStyle {
+	if w < 1 || h < 1 {
+		return c
+	}
+	right, bottom := x+w-1, y+h-1
+	return c.addPath(weight,
+		[2]int{x, y}, [2]int{right, y}, [2]int{right, bottom},
+		[2]int{x, bottom}, [2]int{x, y})
+}
+
+// Line draws a connector from (x1, y1) to (x2, y2). Box-drawing glyphs
+// have no diagonals, so a connector between points that are not aligned
+// runs horizontally first and turns at (x2, y1). Junctions are merged as
+// in [CanvasStyle.HLine].
+func (c *CanvasStyle) Line(x1, y1, x2, y2 int, weight LineWeight) *CanvasStyle {
+	return c.addPath(weight, [2]int{x1, y1}, [2]int{x2, y1}, [2]int{x2, y2})
+}
+
+// addPath adds a line layer following the straight segments between
+// consecutive points. Every cell on the path gets an arm toward each
+// neighbouring cell on the path.
+func (c *CanvasStyle) addPath(weight LineWeight, points ...[2]int) *CanvasStyle {
+	arm := weight.arm()
+	arms := map[[2]int]lineEdges{}
+	minX, minY := points[0][0], points[0][1]
+	maxX, maxY := minX, minY
+	for _, p := range points {
+		if p[0] < minX {
+			minX = p[0]
+		}
+		if p[0] > maxX {
+			maxX = p[0]
+		}
+		if p[1] < minY {
+			minY = p[1]
+		}
+		if p[1] > maxY {
+			maxY = p[1]
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		dx, dy := sign(to[0]-from[0]), sign(to[1]-from[1])
+		// Arm indexes toward the next cell and back toward the previous one.
+		fwd, back := armRight, armLeft
+		switch {
+		case dx < 0:
+			fwd, back = armLeft, armRight
+		case dy > 0:
+			fwd, back = armDown, armUp
+		case dy < 0:
+			fwd, back = armUp, armDown
+		}
+		for p := from; p != to; {
+			next := [2]int{p[0] + dx, p[1] + dy}
+			e := arms[p]
+			e[fwd] = arm
+			arms[p] = e
+			e = arms[next]
+			e[back] = arm
+			arms[next] = e
+			p = next
+		}
+	}
+	if len(arms) == 0 {
+		arms[points[0]] = lineEdges{0, 0, arm, arm}
+	}
+
+	grid := make([][]cell, maxY-minY+1)
+	edges := make([][]lineEdges, len(grid))
+	for row := range grid {
+		grid[row] = make([]cell, maxX-minX+1)
+		edges[row] = make([]lineEdges, len(grid[row]))
+		for col := range grid[row] {
+			grid[row][col] = cell{r: ' '}
+		}
+	}
+	for p, e := range arms {
+		edges[p[1]-minY][p[0]-minX] = e
+	}
+
+	return c.addLayer(layer{
+		grid:    grid,
+		edges:   edges,
+		rounded: weight == LineRounded,
+		x:       minX,
+		y:       minY,
+		z:       c.nextZ,
+	})
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,105 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestMergeLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		below, above rune
+		want         rune
+	}{
+		{"corners side by side", '┐', '┌', '┬'},
+		{"vertical over horizontal", '─', '│', '┼'},
+		{"tee completes to cross", '├', '┤', '┼'},
+		{"same glyph", '─', '─', '─'},
+		{"upper weight wins", '─', '━', '━'},
+		{"heavy and light mix", '│', '━', '┿'},
+		{"double and light mix", '│', '═', '╪'},
+		{"rounded corners", '╮', '╭', '┬'},
+		{"dashed lines", '┄', '┆', '┼'},
+		{"borrowed arm takes upper weight", '└', '╔', '╠'},
+		{"text on top", '│', 'x', 'x'},
+		{"text below", 'x', '│', '│'},
+		{"space below", ' ', '─', '─'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, string(tt.want), string(mergeLines(tt.below, tt.above)))
+		})
+	}
+}
+
+func TestLineGlyphsAreUnique(t *testing.T) {
+	assert.Equal(t, len(lineGlyphs), len(glyphsByEdges))
+}
+
+func TestCanvasLines(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("horizontal and vertical lines", func(t *testing.T) {
+		assert.Equal(t, "───", Canvas().HLine(0, 0, 3, LineLight).String())
+		assert.Equal(t, "┃\n┃", Canvas().VLine(0, 0, 2, LineHeavy).String())
+	})
+
+	t.Run("zero length draws nothing", func(t *testing.T) {
+		assert.Equal(t, "", Canvas().HLine(0, 0, 0, LineLight).String())
+		assert.Equal(t, "", Canvas().Rect(0, 0, 0, 3, LineLight).String())
+	})
+
+	t.Run("rect weights", func(t *testing.T) {
+		assert.Equal(t, "┌─┐\n│ │\n└─┘", Canvas().Rect(0, 0, 3, 3, LineLight).String())
+		assert.Equal(t, "╔═╗\n║ ║\n╚═╝", Canvas().Rect(0, 0, 3, 3, LineDouble).String())
+		assert.Equal(t, "╭─╮\n│ │\n╰─╯", Canvas().Rect(0, 0, 3, 3, LineRounded).String())
+	})
+
+	t.Run("crossing lines", func(t *testing.T) {
+		got := Canvas().HLine(0, 1, 3, LineLight).VLine(1, 0, 3, LineLight).String()
+		assert.Equal(t, " │\n─┼─\n │", got)
+	})
+
+	t.Run("line ending on another forms a tee", func(t *testing.T) {
+		got := Canvas().HLine(0, 2, 3, LineLight).VLine(1, 0, 3, LineLight).String()
+		assert.Equal(t, " │\n │\n─┴─", got)
+	})
+
+	t.Run("mixed weights", func(t *testing.T) {
+		got := Canvas().VLine(1, 0, 3, LineLight).HLine(0, 1, 3, LineHeavy).String()
+		assert.Equal(t, " │\n━┿━\n │", got)
+
+		got = Canvas().HLine(0, 1, 3, LineDouble).VLine(1, 0, 3, LineDouble).String()
+		assert.Equal(t, " ║\n═╬═\n ║", got)
+	})
+
+	t.Run("rects sharing an edge", func(t *testing.T) {
+		got := Canvas().Rect(0, 0, 3, 3, LineLight).Rect(2, 0, 3, 3, LineLight).String()
+		assert.Equal(t, "┌─┬─┐\n│ │ │\n└─┴─┘", got)
+	})
+
+	t.Run("connector turns at the elbow", func(t *testing.T) {
+		got := Canvas().Line(0, 0, 2, 2, LineLight).String()
+		assert.Equal(t, "──┐\n  │\n  │", got)
+
+		got = Canvas().Line(2, 2, 0, 0, LineLight).String()
+		assert.Equal(t, "│\n│\n└──", got)
+	})
+
+	t.Run("lines only cover their own cells", func(t *testing.T) {
+		got := Canvas().Add("abc\ndef\nghi", 0, 0).Rect(0, 0, 3, 3, LineLight).String()
+		assert.Equal(t, "┌─┐\n│e│\n└─┘", got)
+	})
+
+	t.Run("lines join box borders", func(t *testing.T) {
+		got := Canvas().Add(Box().String("abc"), 0, 0).VLine(2, 2, 2, LineLight).String()
+		assert.Equal(t, "┌───┐\n│abc│\n└─┬─┘\n  │", got)
+	})
+
+	t.Run("text over a line replaces it", func(t *testing.T) {
+		got := Canvas().HLine(0, 0, 3, LineLight).Add("x", 1, 0).VLine(1, 0, 2, LineLight).String()
+		assert.Equal(t, "─│─\n │", got)
+	})
+}
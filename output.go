@@ -75,6 +75,37 @@ func isTerminal(w io.Writer) bool {
 	return stat.Mode()&os.ModeCharDevice != 0
 }
 
+// escapeLen returns the length of the escape sequence starting at s[i],
+// which holds an ESC byte: a CSI sequence up to its final byte, an OSC
+// sequence up to BEL or ST, or ESC and the byte after it. A sequence cut
+// off by the end of s runs to the end.
+func escapeLen(s string, i int) int {
+	j := i + 1
+	if j >= len(s) {
+		return 1
+	}
+	switch s[j] {
+	case '[':
+		for j++; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7E {
+				return j + 1 - i
+			}
+		}
+	case ']':
+		for j++; j < len(s); j++ {
+			if s[j] == '\x07' {
+				return j + 1 - i
+			}
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2 - i
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s) - i
+}
+
 func stripANSI(s string) string {
 	if strings.IndexByte(s, '\x1b') < 0 {
 		return s
@@ -90,39 +121,7 @@ func stripANSI(s string) string {
 			i++
 			continue
 		}
-		if i+1 >= len(s) {
-			break
-		}
-		switch s[i+1] {
-		case '[':
-			j := i + 2
-			for j < len(s) && s[j] < 0x40 || s[j] > 0x7E {
-				if s[j] >= 0x40 && s[j] <= 0x7E {
-					break
-				}
-				j++
-			}
-			if j < len(s) {
-				j++
-			}
-			i = j
-		case ']':
-			j := i + 2
-			for j < len(s) {
-				if s[j] == '\x07' {
-					j++
-					break
-				}
-				if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
-					j += 2
-					break
-				}
-				j++
-			}
-			i = j
-		default:
-			i += 2
-		}
+		i += escapeLen(s, i)
 	}
 	return b.String()
 }
@@ -135,3 +134,93 @@ func visibleWidth(s string) int {
 	}
 	return n
 }
+
+// defaultTabWidth is the tab stop interval used when no explicit tab width
+// has been configured.
+const defaultTabWidth = 8
+
+// expandTabs replaces each tab in s with spaces up to the next multiple of
+// width. Escape sequences are copied through and do not advance the column.
+func expandTabs(s string, width int) string {
+	if strings.IndexByte(s, '\t') < 0 {
+		return s
+	}
+	if width <= 0 {
+		width = defaultTabWidth
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + width)
+
+	col := 0
+	i := 0
+	for i < len(s) {
+		switch s[i] {
+		case '\x1b':
+			j := i + escapeLen(s, i)
+			b.WriteString(s[i:j])
+			i = j
+		case '\t':
+			n := width - col%width
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			i++
+		case '\n':
+			b.WriteByte('\n')
+			col = 0
+			i++
+		default:
+			_, size := decodeRune(s[i:])
+			b.WriteString(s[i : i+size])
+			col++
+			i += size
+		}
+	}
+	return b.String()
+}
+
+// wrapLine word-wraps line so that no resulting line is wider than width
+// visible columns. Lines break at the last space that fits; words longer
+// than width are split. Styles active at a break carry over to the next
+// line.
+func wrapLine(line string, width int) []string {
+	if width <= 0 || visibleWidth(line) <= width {
+		return []string{line}
+	}
+
+	cells := parseLine(line)
+	var out []string
+	for len(cells) > width {
+		cut := -1
+		for i := width; i > 0; i-- {
+			if cells[i].r == ' ' {
+				cut = i
+				break
+			}
+		}
+		if cut < 0 {
+			out = append(out, encodeCells(cells[:width]))
+			cells = cells[width:]
+			continue
+		}
+		end := cut
+		for end > 0 && cells[end-1].r == ' ' {
+			end--
+		}
+		out = append(out, encodeCells(cells[:end]))
+		cells = cells[cut+1:]
+	}
+	return append(out, encodeCells(cells))
+}
+
+// truncateLine cuts line down to at most width visible columns, keeping
+// the styles of the cells that remain.
+func truncateLine(line string, width int) string {
+	if visibleWidth(line) <= width {
+		return line
+	}
+	if width <= 0 {
+		return ""
+	}
+	return encodeCells(parseLine(line)[:width])
+}
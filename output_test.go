@@ -95,6 +95,34 @@ func TestStripANSI(t *testing.T) {
 	})
 }
 
+func TestEscapeLen(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"csi", "\x1b[1;31mx", 7},
+		{"osc with bel", "\x1b]8;;u\x07x", 7},
+		{"osc with st", "\x1b]8;;u\x1b\\x", 8},
+		{"two-byte escape", "\x1b7x", 2},
+		{"lone esc", "\x1b", 1},
+		{"unterminated csi", "\x1b[12", 4},
+		{"unterminated osc", "\x1b]8;;u", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeLen("a"+tt.s, 1))
+		})
+	}
+
+	t.Run("callers agree", func(t *testing.T) {
+		s := "a\x1b]8;;u\x1b\\b\x1b[1mc\x1b[12"
+		assert.Equal(t, "abc", stripANSI(s))
+		assert.Equal(t, 3, len(parseLine(s)))
+		assert.Equal(t, "\x1b]8;;u\x1b\\a   b", expandTabs("\x1b]8;;u\x1b\\a\tb", 4))
+	})
+}
+
 func TestVisibleWidth(t *testing.T) {
 	t.Run("plain text", func(t *testing.T) {
 		assert.Equal(t, 5, visibleWidth("hello"))
@@ -113,3 +141,56 @@ func TestVisibleWidth(t *testing.T) {
 		assert.Equal(t, 4, visibleWidth("hola"))
 	})
 }
+
+func TestExpandTabs(t *testing.T) {
+	t.Run("no tabs unchanged", func(t *testing.T) {
+		assert.Equal(t, "hello", expandTabs("hello", 8))
+	})
+
+	t.Run("expands to next tab stop", func(t *testing.T) {
+		assert.Equal(t, "ab      c", expandTabs("ab\tc", 8))
+		assert.Equal(t, "abcd    e", expandTabs("abcd\te", 4))
+	})
+
+	t.Run("consecutive tabs", func(t *testing.T) {
+		assert.Equal(t, "a   b", expandTabs("a\t\tb", 2))
+	})
+
+	t.Run("non-positive width uses default", func(t *testing.T) {
+		assert.Equal(t, "        x", expandTabs("\tx", 0))
+	})
+
+	t.Run("escape sequences do not advance column", func(t *testing.T) {
+		assert.Equal(t, "\x1b[31mab\x1b[0m  c", expandTabs("\x1b[31mab\x1b[0m\tc", 4))
+	})
+
+	t.Run("newline resets column", func(t *testing.T) {
+		assert.Equal(t, "abc \nx   y", expandTabs("abc\t\nx\ty", 4))
+	})
+}
+
+func TestWrapLine(t *testing.T) {
+	t.Run("short line unchanged", func(t *testing.T) {
+		assert.Equal(t, []string{"hello"}, wrapLine("hello", 10))
+	})
+
+	t.Run("breaks at spaces", func(t *testing.T) {
+		assert.Equal(t, []string{"hello", "world"}, wrapLine("hello world", 7))
+	})
+
+	t.Run("splits long words", func(t *testing.T) {
+		assert.Equal(t, []string{"abcd", "ef"}, wrapLine("abcdef", 4))
+	})
+
+	t.Run("keeps styles across breaks", func(t *testing.T) {
+		got := wrapLine("\x1b[31mab cd\x1b[0m", 2)
+		assert.Equal(t, []string{"\x1b[31mab\x1b[0m", "\x1b[31mcd\x1b[0m"}, got)
+	})
+}
+
+func TestTruncateLine(t *testing.T) {
+	assert.Equal(t, "abc", truncateLine("abc", 5))
+	assert.Equal(t, "ab", truncateLine("abc", 2))
+	assert.Equal(t, "", truncateLine("abc", 0))
+	assert.Equal(t, "\x1b[31mab\x1b[0m", truncateLine("\x1b[31mabc\x1b[0m", 2))
+}
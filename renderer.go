@@ -0,0 +1,151 @@
+package tinta
+
+import (
+	"bytes"
+	"io"
+)
+
+// Escape sequences used by Renderer.
+const (
+	syncBegin   = "\x1b[?2026h"
+	syncEnd     = "\x1b[?2026l"
+	clearScreen = "\x1b[2J"
+)
+
+// Renderer draws successive frames of a [Screen] to a terminal, writing
+// only the cells that changed since the previous frame. Changed cells are
+// reached with cursor-positioning sequences, nearby changes are written
+// as one run, and styles are switched with the shortest SGR transition.
+//
+// Each frame is wrapped in synchronized-output sequences (DEC mode 2026),
+// so terminals that support them show it at once, without flicker; other
+// terminals ignore them. A frame without changes writes nothing.
+//
+// The renderer assumes it owns the terminal screen. Call
+// [Renderer.Invalidate] after anything else writes to it. A Renderer is
+// not safe for concurrent use.
+type Renderer struct {
+	w    io.Writer
+	buf  bytes.Buffer
+	prev []cell
+
+	// width and height are the size of the previous frame, and valid
+	// whether prev matches what the terminal shows.
+	width, height int
+	valid         bool
+}
+
+// NewRenderer returns a [Renderer] writing frames to w.
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{w: w}
+}
+
+// Invalidate makes the next frame redraw every cell, for instance after
+// the terminal was cleared or written to by something else.
+func (r *Renderer) Invalidate() {
+	r.valid = false
+}
+
+// Render writes the cells of s that differ from the previous frame. The
+// first frame, and any frame after [Renderer.Invalidate], draws every
+// cell; a frame whose size differs from the previous one also clears the
+// terminal first.
+func (r *Renderer) Render(s *Screen) error {
+	full := !r.valid || s.width != r.width || s.height != r.height
+
+	r.buf.Reset()
+	r.buf.WriteString(syncBegin)
+	if r.valid && full {
+		r.buf.WriteString(clearScreen)
+	}
+	start := r.buf.Len()
+
+	changed := func(i int) bool {
+		return full || r.prev[i] != s.cells[i]
+	}
+
+	// The cursor position is tracked to skip moves to where it already
+	// is; -1 means unknown, which is also the case after writing the last
+	// column, where terminals differ in how they wrap.
+	curX, curY := -1, -1
+	var cur sgr
+	for y := 0; y < s.height; y++ {
+		row := y * s.width
+		for x := 0; x < s.width; {
+			if !changed(row + x) {
+				x++
+				continue
+			}
+
+			// Extend the run over short gaps of unchanged cells, which are
+			// cheaper to rewrite than to skip with a cursor move.
+			end := x + 1
+			for j := end; j < s.width; {
+				if changed(row + j) {
+					j++
+					end = j
+					continue
+				}
+				gap := j
+				for j < s.width && !changed(row+j) {
+					j++
+				}
+				if j == s.width || j-gap > cursorMoveSize(j, y) {
+					break
+				}
+			}
+
+			if x != curX || y != curY {
+				writeCursorMove(&r.buf, x, y)
+			}
+			for _, cl := range s.cells[row+x : row+end] {
+				cl.style.writeTransition(&r.buf, cur)
+				cur = cl.style
+				r.buf.WriteRune(cl.r)
+			}
+			curX, curY = end, y
+			if end == s.width {
+				curX = -1
+			}
+			x = end
+		}
+	}
+	if !cur.isZero() {
+		r.buf.WriteString(cReset)
+	}
+
+	if cap(r.prev) < len(s.cells) {
+		r.prev = make([]cell, len(s.cells))
+	}
+	r.prev = r.prev[:len(s.cells)]
+	copy(r.prev, s.cells)
+	r.width, r.height = s.width, s.height
+	r.valid = true
+
+	if r.buf.Len() == start && !full {
+		return nil
+	}
+	r.buf.WriteString(syncEnd)
+	_, err := r.w.Write(r.buf.Bytes())
+	if err != nil {
+		// What the terminal shows is unknown after a failed write.
+		r.valid = false
+	}
+	return err
+}
+
+// writeCursorMove writes the sequence moving the cursor to column x and
+// row y, counted from zero.
+func writeCursorMove(buf cellWriter, x, y int) {
+	buf.WriteString("\x1b[")
+	writeInt(buf, y+1)
+	buf.WriteByte(';')
+	writeInt(buf, x+1)
+	buf.WriteByte('H')
+}
+
+// cursorMoveSize returns the length of the sequence moving the cursor to
+// column x and row y.
+func cursorMoveSize(x, y int) int {
+	return 4 + writeInt(nil, y+1) + writeInt(nil, x+1)
+}
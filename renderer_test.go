@@ -0,0 +1,106 @@
+package tinta
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestRenderer(t *testing.T) {
+	ForceColors(true)
+
+	var out strings.Builder
+	r := NewRenderer(&out)
+	s := NewScreen(6, 2)
+	frame := func() string {
+		out.Reset()
+		assert.Equal(t, nil, r.Render(s))
+		return out.String()
+	}
+
+	s.DrawString(0, 0, "hello")
+	assert.Equal(t, "\x1b[?2026h\x1b[1;1Hhello \x1b[2;1H      \x1b[?2026l", frame())
+
+	t.Run("unchanged frame writes nothing", func(t *testing.T) {
+		assert.Equal(t, "", frame())
+	})
+
+	t.Run("only changed cells are written", func(t *testing.T) {
+		s.Set(4, 1, 'x', nil)
+		assert.Equal(t, "\x1b[?2026h\x1b[2;5Hx\x1b[?2026l", frame())
+	})
+
+	t.Run("short gaps are coalesced", func(t *testing.T) {
+		s.Set(0, 0, 'j', nil)
+		s.Set(3, 0, 'L', nil)
+		assert.Equal(t, "\x1b[?2026h\x1b[1;1HjelL\x1b[?2026l", frame())
+	})
+
+	t.Run("long gaps move the cursor", func(t *testing.T) {
+		wide := NewScreen(30, 1)
+		var out strings.Builder
+		r := NewRenderer(&out)
+		r.Render(wide)
+		out.Reset()
+		wide.Set(1, 0, 'a', nil)
+		wide.Set(20, 0, 'b', nil)
+		r.Render(wide)
+		assert.Equal(t, "\x1b[?2026h\x1b[1;2Ha\x1b[1;21Hb\x1b[?2026l", out.String())
+	})
+
+	t.Run("minimal style transitions", func(t *testing.T) {
+		s.Set(0, 1, 'a', Text().Red())
+		s.Set(1, 1, 'b', Text().Red().Bold())
+		s.Set(2, 1, 'c', Text().Bold())
+		assert.Equal(t, "\x1b[?2026h\x1b[2;1H\x1b[31ma\x1b[1mb\x1b[39mc\x1b[0m\x1b[?2026l", frame())
+	})
+
+	t.Run("invalidate redraws everything", func(t *testing.T) {
+		r.Invalidate()
+		got := frame()
+		assert.Equal(t, true, strings.Contains(got, "\x1b[1;1HjelLo \x1b[2;1H"))
+		assert.Equal(t, false, strings.Contains(got, clearScreen))
+	})
+
+	t.Run("resize clears the terminal", func(t *testing.T) {
+		s.Resize(2, 1)
+		assert.Equal(t, "\x1b[?2026h\x1b[2J\x1b[1;1H  \x1b[?2026l", frame())
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestRendererWriteError(t *testing.T) {
+	r := NewRenderer(failingWriter{})
+	s := NewScreen(2, 1)
+	assert.Equal(t, "closed", r.Render(s).Error())
+
+	// A failed frame is redrawn in full.
+	var out strings.Builder
+	r.w = &out
+	assert.Equal(t, nil, r.Render(s))
+	assert.Equal(t, "\x1b[?2026h\x1b[1;1H  \x1b[?2026l", out.String())
+}
+
+func TestRendererAllocs(t *testing.T) {
+	ForceColors(true)
+
+	r := NewRenderer(io.Discard)
+	s := NewScreen(40, 10)
+	red := Text().Red()
+	n := 0
+	frame := func() {
+		n++
+		s.Clear()
+		s.Set(n%40, n%10, '@', red)
+		r.Render(s)
+	}
+	frame()
+
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, frame))
+}
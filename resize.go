@@ -0,0 +1,66 @@
+package tinta
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// resizeDebounce is how long the terminal must stay quiet after a resize
+// event before the new size is reported.
+const resizeDebounce = 50 * time.Millisecond
+
+// OnResize calls fn with the new terminal size, as reported by
+// [TerminalSize] for the default output, whenever the terminal is resized.
+// Bursts of resize events are debounced into a single call, and calls are
+// skipped when the size did not actually change. Watching stops when ctx
+// is done.
+//
+// OnResize returns immediately; fn runs on a separate goroutine, one call
+// at a time. On Linux, macOS and the BSDs resizes are detected through
+// SIGWINCH and the size is queried from the terminal. On other platforms,
+// where the size cannot be queried, OnResize does nothing.
+func OnResize(ctx context.Context, fn func(cols, rows int)) {
+	if resizeSignal == nil {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, resizeSignal)
+	size := func() (int, int) { return TerminalSize(getOutput()) }
+	cols, rows := size()
+	go func() {
+		defer signal.Stop(ch)
+		watchResize(ctx, ch, fn, size, cols, rows, resizeDebounce)
+	}()
+}
+
+func watchResize(ctx context.Context, events <-chan os.Signal, fn func(cols, rows int), size func() (int, int), lastCols, lastRows int, debounce time.Duration) {
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-events:
+			if pending && !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(debounce)
+			pending = true
+		case <-timer.C:
+			pending = false
+			cols, rows := size()
+			if cols == lastCols && rows == lastRows {
+				continue
+			}
+			lastCols, lastRows = cols, rows
+			fn(cols, rows)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package tinta
+
+import "os"
+
+var resizeSignal os.Signal
@@ -0,0 +1,98 @@
+package tinta
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+type fakeSize struct {
+	mu         sync.Mutex
+	cols, rows int
+}
+
+func (f *fakeSize) set(cols, rows int) {
+	f.mu.Lock()
+	f.cols, f.rows = cols, rows
+	f.mu.Unlock()
+}
+
+func (f *fakeSize) get() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cols, f.rows
+}
+
+func TestWatchResize(t *testing.T) {
+	t.Run("debounces bursts into one call", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		size := &fakeSize{cols: 80, rows: 24}
+		events := make(chan os.Signal, 3)
+		calls := make(chan [2]int, 10)
+		done := make(chan struct{})
+		go func() {
+			watchResize(ctx, events, func(cols, rows int) { calls <- [2]int{cols, rows} }, size.get, 80, 24, 20*time.Millisecond)
+			close(done)
+		}()
+
+		size.set(100, 30)
+		events <- os.Interrupt
+		events <- os.Interrupt
+		events <- os.Interrupt
+
+		select {
+		case got := <-calls:
+			assert.Equal(t, [2]int{100, 30}, got)
+		case <-time.After(time.Second):
+			t.Fatal("callback not called")
+		}
+
+		select {
+		case got := <-calls:
+			t.Errorf("unexpected extra call %v", got)
+		case <-time.After(60 * time.Millisecond):
+		}
+
+		cancel()
+		<-done
+	})
+
+	t.Run("unchanged size is not reported", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		size := &fakeSize{cols: 80, rows: 24}
+		events := make(chan os.Signal, 1)
+		calls := make(chan [2]int, 10)
+		go watchResize(ctx, events, func(cols, rows int) { calls <- [2]int{cols, rows} }, size.get, 80, 24, 5*time.Millisecond)
+
+		events <- os.Interrupt
+		select {
+		case got := <-calls:
+			t.Errorf("unexpected call %v", got)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		size := &fakeSize{cols: 80, rows: 24}
+		done := make(chan struct{})
+		go func() {
+			watchResize(ctx, make(chan os.Signal), func(int, int) {}, size.get, 80, 24, time.Millisecond)
+			close(done)
+		}()
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("watcher did not stop")
+		}
+	})
+}
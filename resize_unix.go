@@ -0,0 +1,10 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package tinta
+
+import (
+	"os"
+	"syscall"
+)
+
+var resizeSignal os.Signal = syscall.SIGWINCH
@@ -0,0 +1,186 @@
+package tinta
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Screen is a mutable grid of cells for drawing frames at a high rate.
+// Unlike [CanvasStyle], its methods change it in place and allocate
+// nothing once the grid and output buffer have grown to size, so one
+// Screen can be cleared, redrawn and flushed every frame.
+//
+// Cells are composited like canvas layers: drawn cells replace the cells
+// below, except that a cell without a background keeps the one below.
+//
+// A Screen is not safe for concurrent use.
+type Screen struct {
+	width, height int
+	cells         []cell
+	buf           bytes.Buffer
+
+	// styles caches the parsed style of each TextStyle passed to Set, by
+	// whether colors were enabled.
+	styles map[screenStyleKey]sgr
+}
+
+// maxScreenStyles bounds the style cache of a Screen.
+const maxScreenStyles = 256
+
+type screenStyleKey struct {
+	style   *TextStyle
+	enabled bool
+}
+
+// NewScreen returns a blank w x h [Screen].
+func NewScreen(w, h int) *Screen {
+	s := &Screen{}
+	s.Resize(w, h)
+	return s
+}
+
+// Size returns the width and height of the screen.
+func (s *Screen) Size() (w, h int) {
+	return s.width, s.height
+}
+
+// Resize changes the size of the screen and clears it. The grid is only
+// reallocated when it grows past its capacity.
+func (s *Screen) Resize(w, h int) {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	if n := w * h; n > cap(s.cells) {
+		s.cells = make([]cell, n)
+	} else {
+		s.cells = s.cells[:n]
+	}
+	s.width, s.height = w, h
+	s.Clear()
+}
+
+// Clear resets every cell to an unstyled space.
+func (s *Screen) Clear() {
+	for i := range s.cells {
+		s.cells[i] = cell{r: ' '}
+	}
+}
+
+// Set draws rune r at (x, y) with an optional style. Positions outside
+// the screen are ignored.
+func (s *Screen) Set(x, y int, r rune, style *TextStyle) {
+	s.put(x, y, cell{r: r, style: s.style(style)})
+}
+
+// DrawString draws a rendered string, which may contain ANSI styles and
+// several lines, with its top-left corner at (x, y). Tabs are expanded to
+// stops every 8 columns and the parts outside the screen are cut off.
+func (s *Screen) DrawString(x, y int, str string) {
+	str = expandTabs(str, defaultTabWidth)
+	for row := y; ; row++ {
+		line := str
+		end := strings.IndexByte(str, '\n')
+		if end >= 0 {
+			line = str[:end]
+		}
+		if row >= 0 && row < s.height {
+			col := x
+			scanLine(line, func(cl cell) {
+				s.put(col, row, cl)
+				col++
+			})
+		}
+		if end < 0 {
+			return
+		}
+		str = str[end+1:]
+	}
+}
+
+// Blit composites a canvas onto the screen with the canvas's point (0, 0)
+// at (x, y), like [CanvasStyle.AddCanvas]. See-through canvas cells leave
+// the screen unchanged.
+func (s *Screen) Blit(c *CanvasStyle, x, y int) {
+	grid, originX, originY := c.cells()
+	for rowIdx, row := range grid {
+		for colIdx, cl := range row {
+			if !cl.clear {
+				s.put(x+originX+colIdx, y+originY+rowIdx, cl)
+			}
+		}
+	}
+}
+
+// Cell returns the cell at (x, y), or a zero Cell outside the screen.
+func (s *Screen) Cell(x, y int) Cell {
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return Cell{}
+	}
+	return s.cells[y*s.width+x].exported()
+}
+
+// Flush writes the whole screen to w as one frame: the cursor is moved
+// to the top-left corner and every row is written in full, so that the
+// frame replaces the previous one.
+func (s *Screen) Flush(w io.Writer) error {
+	s.buf.Reset()
+	s.buf.WriteString("\x1b[H")
+	for row := 0; row < s.height; row++ {
+		if row > 0 {
+			s.buf.WriteString("\r\n")
+		}
+		writeCells(&s.buf, s.row(row))
+	}
+	_, err := w.Write(s.buf.Bytes())
+	return err
+}
+
+// String renders the screen like [CanvasStyle.String]: rows joined by
+// newlines, without trailing unstyled spaces.
+func (s *Screen) String() string {
+	var buf strings.Builder
+	for row := 0; row < s.height; row++ {
+		if row > 0 {
+			buf.WriteByte('\n')
+		}
+		writeCells(&buf, trimCells(s.row(row)))
+	}
+	return buf.String()
+}
+
+func (s *Screen) row(y int) []cell {
+	return s.cells[y*s.width : (y+1)*s.width]
+}
+
+// put draws cl at (x, y), keeping the background below when cl has none.
+func (s *Screen) put(x, y int, cl cell) {
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return
+	}
+	i := y*s.width + x
+	cl.style = cl.style.over(s.cells[i].style)
+	cl.clear = false
+	s.cells[i] = cl
+}
+
+// style returns the parsed style of t, parsing it once per TextStyle.
+func (s *Screen) style(t *TextStyle) sgr {
+	if t == nil {
+		return sgr{}
+	}
+	key := screenStyleKey{t, isEnabled()}
+	if p, ok := s.styles[key]; ok {
+		return p
+	}
+	// Styles built anew every frame would grow the cache without bound.
+	if s.styles == nil || len(s.styles) >= maxScreenStyles {
+		s.styles = map[screenStyleKey]sgr{}
+	}
+	p := textSGR(t)
+	s.styles[key] = p
+	return p
+}
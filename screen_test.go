@@ -0,0 +1,99 @@
+package tinta
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestScreen(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("draw and clear", func(t *testing.T) {
+		s := NewScreen(6, 3)
+		s.DrawString(1, 0, "ab\ncd")
+		s.Set(5, 2, '*', nil)
+		assert.Equal(t, " ab\n cd\n     *", s.String())
+
+		s.Clear()
+		assert.Equal(t, "\n\n", s.String())
+	})
+
+	t.Run("drawing is cut at the edges", func(t *testing.T) {
+		s := NewScreen(3, 2)
+		s.DrawString(-1, -1, "abcd\nefgh\nijkl")
+		s.Set(3, 0, '*', nil)
+		assert.Equal(t, "fgh\njkl", s.String())
+	})
+
+	t.Run("tabs", func(t *testing.T) {
+		s := NewScreen(10, 1)
+		s.DrawString(0, 0, "a\tb")
+		assert.Equal(t, 'b', s.Cell(8, 0).Rune)
+	})
+
+	t.Run("blit keeps see-through cells", func(t *testing.T) {
+		s := NewScreen(5, 1)
+		s.DrawString(0, 0, "-----")
+		s.Blit(Canvas().AddTransparent("x x", 0, 0), 1, 0)
+		assert.Equal(t, "-x-x-", s.String())
+	})
+
+	t.Run("blit places the canvas origin", func(t *testing.T) {
+		s := NewScreen(5, 1)
+		s.Blit(Canvas().Add("ab", -1, 0).Add("c", 2, 0), 2, 0)
+		assert.Equal(t, " ab c", s.String())
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		s := NewScreen(4, 4)
+		s.DrawString(0, 0, "abcd")
+		s.Resize(2, 1)
+		w, h := s.Size()
+		assert.Equal(t, 2, w)
+		assert.Equal(t, 1, h)
+		assert.Equal(t, "", s.String())
+	})
+
+	t.Run("flush writes full rows", func(t *testing.T) {
+		s := NewScreen(3, 2)
+		s.DrawString(0, 0, "ab")
+		var out strings.Builder
+		assert.Equal(t, nil, s.Flush(&out))
+		assert.Equal(t, "\x1b[Hab \r\n   ", out.String())
+	})
+}
+
+func TestScreenStyles(t *testing.T) {
+	ForceColors(true)
+
+	s := NewScreen(4, 1)
+	s.DrawString(0, 0, Text().OnBlue().String("    "))
+	s.Set(1, 0, 'x', Text().Red())
+	s.DrawString(2, 0, Text().Bold().String("y"))
+
+	assert.Equal(t, "31", s.Cell(1, 0).Fg)
+	assert.Equal(t, "44", s.Cell(1, 0).Bg)
+	assert.Equal(t, true, s.Cell(2, 0).Bold)
+	assert.Equal(t, "\x1b[44m \x1b[31mx\x1b[1;39my\x1b[22m \x1b[0m", s.String())
+}
+
+func TestScreenAllocs(t *testing.T) {
+	ForceColors(true)
+
+	red := Text().Red()
+	label := Text().Bold().OnBlue().String("frame counter")
+	s := NewScreen(40, 10)
+	frame := func() {
+		s.Clear()
+		s.DrawString(2, 1, label)
+		s.Set(0, 0, '@', red)
+		s.Flush(io.Discard)
+	}
+	frame()
+
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, frame))
+}
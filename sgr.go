@@ -0,0 +1,540 @@
+package tinta
+
+import (
+	"strconv"
+	"strings"
+)
+
+// colorMode tells how the value of an sgrColor is encoded.
+type colorMode uint8
+
+const (
+	colorNone  colorMode = iota // terminal default
+	colorBasic                  // index 0-15 in r: 30-37 and 90-97
+	colorIndex                  // 256-color palette index in r
+	colorRGB                    // 24-bit color
+)
+
+// sgrColor is a foreground or background color parsed from SGR codes.
+type sgrColor struct {
+	mode    colorMode
+	r, g, b uint8
+}
+
+// sgrFlags holds the on/off text attributes of a cell.
+type sgrFlags uint16
+
+const (
+	flagBold sgrFlags = 1 << iota
+	flagDim
+	flagItalic
+	flagUnderline
+	flagBlink
+	flagInvert
+	flagHidden
+	flagStrike
+)
+
+// flagCodes lists each flag with the SGR codes that set and clear it, in
+// the order they are emitted.
+var flagCodes = []struct {
+	flag    sgrFlags
+	on, off int
+}{
+	{flagBold, 1, 22},
+	{flagDim, 2, 22},
+	{flagItalic, 3, 23},
+	{flagUnderline, 4, 24},
+	{flagBlink, 5, 25},
+	{flagInvert, 7, 27},
+	{flagHidden, 8, 28},
+	{flagStrike, 9, 29},
+}
+
+// sgr is the parsed style of one cell: its colors and flags, plus any
+// escape sequences other than SGR, such as hyperlinks, kept verbatim.
+type sgr struct {
+	fg, bg sgrColor
+	flags  sgrFlags
+	// underline is the style n of a 4:n underline other than a plain one,
+	// such as 3 for curly; 0 means none. It excludes flagUnderline.
+	underline uint8
+	// ulColor is the underline color, set with 58 and cleared with 59.
+	ulColor sgrColor
+	// unknown holds the other SGR parameters not modelled above verbatim,
+	// each once, joined by ';'.
+	unknown string
+	extra   string
+}
+
+// textSGR returns the style t applies to text, which is empty when t is
+// nil or colors are disabled.
+func textSGR(t *TextStyle) sgr {
+	if t == nil {
+		return sgr{}
+	}
+	return parseLine(t.render(" "))[0].style
+}
+
+// isZero reports whether the cell is unstyled.
+func (p sgr) isZero() bool {
+	return p == sgr{}
+}
+
+// over returns the style of a cell drawn over a cell styled with below.
+// Colors and flags belong to the upper glyph, except that an upper cell
+// without a background keeps the background of the cell below, so text
+// placed on a filled panel keeps the panel color.
+func (p sgr) over(below sgr) sgr {
+	if p.bg.mode == colorNone {
+		p.bg = below.bg
+	}
+	return p
+}
+
+// under returns the style of a cell drawn in a region styled with base,
+// such as a table row: the cell keeps its own colors and attributes and
+// takes those of base where it has none.
+func (p sgr) under(base sgr) sgr {
+	if p.fg.mode == colorNone {
+		p.fg = base.fg
+	}
+	if p.bg.mode == colorNone {
+		p.bg = base.bg
+	}
+	p.flags |= base.flags
+	if p.underline == 0 {
+		p.underline = base.underline
+	} else {
+		p.flags &^= flagUnderline
+	}
+	if p.ulColor.mode == colorNone {
+		p.ulColor = base.ulColor
+	}
+	if p.unknown == "" {
+		p.unknown = base.unknown
+	}
+	return p
+}
+
+// apply updates p with one escape sequence. It reports false when seq is
+// not an SGR sequence, leaving p unchanged.
+func (p *sgr) apply(seq string) bool {
+	if len(seq) < 3 || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return false
+	}
+	body := seq[2 : len(seq)-1]
+	// Private markers such as '?' and intermediate bytes make it some
+	// other sequence ending in 'm'.
+	for i := 0; i < len(body); i++ {
+		if c := body[i]; (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false
+		}
+	}
+
+	// Parameters are split into a fixed array so that common sequences
+	// do not allocate. Empty parameters are skipped.
+	var buf [16]string
+	params := splitParams(buf[:0], body, ';')
+	if len(params) == 0 {
+		*p = sgr{}
+		return true
+	}
+
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if strings.IndexByte(param, ':') >= 0 {
+			p.applyGroup(param)
+			continue
+		}
+		code := atoi(param)
+		switch {
+		case code == 0:
+			*p = sgr{}
+		case code == 22:
+			p.flags &^= flagBold | flagDim
+		case code >= 30 && code <= 37:
+			p.fg = sgrColor{mode: colorBasic, r: uint8(code - 30)}
+		case code >= 90 && code <= 97:
+			p.fg = sgrColor{mode: colorBasic, r: uint8(code - 90 + 8)}
+		case code >= 40 && code <= 47:
+			p.bg = sgrColor{mode: colorBasic, r: uint8(code - 40)}
+		case code >= 100 && code <= 107:
+			p.bg = sgrColor{mode: colorBasic, r: uint8(code - 100 + 8)}
+		case code == 39:
+			p.fg = sgrColor{}
+		case code == 49:
+			p.bg = sgrColor{}
+		case code == 38 || code == 48 || code == 58:
+			c, n := extendedColor(params[i+1:])
+			i += n
+			switch code {
+			case 38:
+				p.fg = c
+			case 48:
+				p.bg = c
+			default:
+				p.ulColor = c
+			}
+		case code == 59:
+			p.ulColor = sgrColor{}
+		case code == 4 || code == 24:
+			p.underline = 0
+			p.applyFlag(code)
+		default:
+			if !p.applyFlag(code) {
+				p.addUnknown(param)
+			}
+		}
+	}
+	return true
+}
+
+// applyFlag sets or clears the flag code stands for. It reports false
+// when code is not a flag code.
+func (p *sgr) applyFlag(code int) bool {
+	for _, fc := range flagCodes {
+		switch code {
+		case fc.on:
+			p.flags |= fc.flag
+			return true
+		case fc.off:
+			p.flags &^= fc.flag
+			return true
+		}
+	}
+	return false
+}
+
+// applyGroup applies a parameter with colon-separated sub-parameters,
+// such as 4:3 or 38:2::255:0:0, as a single attribute.
+func (p *sgr) applyGroup(group string) {
+	var buf [8]string
+	subs := splitParams(buf[:0], group, ':')
+	if len(subs) == 0 {
+		return
+	}
+	switch atoi(subs[0]) {
+	case 4:
+		// 4:0 and 4:1 are plain underline off and on; other styles such
+		// as curly underlines replace it.
+		if len(subs) == 2 {
+			switch n := atoi(subs[1]); n {
+			case 0, 1:
+				p.underline = 0
+				p.applyFlag(24 - 20*n)
+			default:
+				p.underline = uint8(n)
+				p.flags &^= flagUnderline
+			}
+			return
+		}
+	case 38, 48, 58:
+		// The 24-bit form may carry a color space id: 38:2:id:r:g:b.
+		args := subs[1:]
+		if len(args) == 5 && args[0] == "2" {
+			args = append(args[:1:1], args[2:]...)
+		}
+		if c, n := extendedColor(args); c.mode != colorNone && n == len(args) {
+			switch atoi(subs[0]) {
+			case 38:
+				p.fg = c
+			case 48:
+				p.bg = c
+			default:
+				p.ulColor = c
+			}
+			return
+		}
+	}
+	p.addUnknown(group)
+}
+
+// addUnknown keeps param verbatim, unless it is kept already.
+func (p *sgr) addUnknown(param string) {
+	if p.unknown == "" {
+		p.unknown = param
+		return
+	}
+	for rest := p.unknown; rest != ""; {
+		field := rest
+		if i := strings.IndexByte(rest, ';'); i >= 0 {
+			field, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = ""
+		}
+		if field == param {
+			return
+		}
+	}
+	p.unknown += ";" + param
+}
+
+// splitParams appends the non-empty fields of s separated by sep to dst.
+func splitParams(dst []string, s string, sep byte) []string {
+	for s != "" {
+		field := s
+		if i := strings.IndexByte(s, sep); i >= 0 {
+			field, s = s[:i], s[i+1:]
+		} else {
+			s = ""
+		}
+		if field != "" {
+			dst = append(dst, field)
+		}
+	}
+	return dst
+}
+
+// atoi parses a parameter made of digits; it saturates instead of
+// overflowing.
+func atoi(s string) int {
+	v := 0
+	for i := 0; i < len(s); i++ {
+		if v < 1<<20 {
+			v = v*10 + int(s[i]-'0')
+		}
+	}
+	return v
+}
+
+// extendedColor parses the arguments of a 38 or 48 code: 5;n for the
+// 256-color palette or 2;r;g;b for 24-bit color. It returns the color and
+// how many arguments it consumed.
+func extendedColor(args []string) (sgrColor, int) {
+	if len(args) >= 2 && args[0] == "5" {
+		return sgrColor{mode: colorIndex, r: uint8(atoi(args[1]))}, 2
+	}
+	if len(args) >= 4 && args[0] == "2" {
+		return sgrColor{mode: colorRGB, r: uint8(atoi(args[1])), g: uint8(atoi(args[2])), b: uint8(atoi(args[3]))}, 4
+	}
+	return sgrColor{}, len(args)
+}
+
+// appendCode appends the SGR parameters selecting c, using base 30 for
+// foreground or 40 for background.
+func (c sgrColor) appendCode(codes []string, base int) []string {
+	switch c.mode {
+	case colorBasic:
+		if c.r < 8 {
+			return append(codes, strconv.Itoa(base+int(c.r)))
+		}
+		return append(codes, strconv.Itoa(base+60+int(c.r)-8))
+	case colorIndex:
+		return append(codes, strconv.Itoa(base+8), "5", strconv.Itoa(int(c.r)))
+	case colorRGB:
+		return append(codes, strconv.Itoa(base+8), "2",
+			strconv.Itoa(int(c.r)), strconv.Itoa(int(c.g)), strconv.Itoa(int(c.b)))
+	}
+	return codes
+}
+
+// String returns the escape sequences that set p from an unstyled state.
+func (p sgr) String() string {
+	var buf strings.Builder
+	p.write(&buf)
+	return buf.String()
+}
+
+// write writes the escape sequences that set p from an unstyled state to
+// buf, without allocating.
+func (p sgr) write(buf cellWriter) {
+	w := sgrWriter{buf: buf}
+	p.codes(&w)
+	w.end()
+	buf.WriteString(p.extra)
+}
+
+// writeTransition writes the shortest escape sequences that change the
+// style of a terminal from from to p. Only the attributes that differ are
+// updated, unless a reset followed by all of p's codes is shorter.
+func (p sgr) writeTransition(buf cellWriter, from sgr) {
+	if p == from {
+		return
+	}
+	var diff, reset sgrWriter
+	p.diffCodes(&diff, from)
+	reset.code(0)
+	p.codes(&reset)
+
+	// Attributes kept verbatim can only be undone with a reset.
+	mustReset := from.unknown != "" && p.unknown != from.unknown
+
+	w := sgrWriter{buf: buf}
+	if mustReset || reset.size < diff.size {
+		w.code(0)
+		p.codes(&w)
+	} else {
+		p.diffCodes(&w, from)
+	}
+	w.end()
+	if p.extra != from.extra {
+		buf.WriteString(p.extra)
+	}
+}
+
+// codes writes the SGR parameters that set p from an unstyled state.
+func (p sgr) codes(w *sgrWriter) {
+	for _, fc := range flagCodes {
+		if p.flags&fc.flag != 0 {
+			w.code(fc.on)
+		}
+	}
+	w.color(p.fg, 30)
+	w.color(p.bg, 40)
+	if p.underline != 0 {
+		w.underline(p.underline)
+	}
+	w.color(p.ulColor, 50)
+	if p.unknown != "" {
+		w.param(p.unknown)
+	}
+}
+
+// diffCodes writes the SGR parameters that change from into p.
+func (p sgr) diffCodes(w *sgrWriter, from sgr) {
+	off := from.flags &^ p.flags
+	on := p.flags &^ from.flags
+	// Bold and dim share their off code, so clearing one clears both.
+	if off&(flagBold|flagDim) != 0 {
+		w.code(22)
+		on |= p.flags & (flagBold | flagDim)
+	}
+	for _, fc := range flagCodes {
+		if off&fc.flag != 0 && fc.off != 22 {
+			w.code(fc.off)
+		}
+	}
+	for _, fc := range flagCodes {
+		if on&fc.flag != 0 {
+			w.code(fc.on)
+		}
+	}
+	if p.fg != from.fg {
+		if p.fg.mode == colorNone {
+			w.code(39)
+		} else {
+			w.color(p.fg, 30)
+		}
+	}
+	if p.bg != from.bg {
+		if p.bg.mode == colorNone {
+			w.code(49)
+		} else {
+			w.color(p.bg, 40)
+		}
+	}
+	if p.underline != from.underline {
+		switch {
+		case p.underline != 0:
+			w.underline(p.underline)
+		case p.flags&flagUnderline == 0:
+			// A plain underline, when p has one, was turned on above,
+			// which also ends the other style.
+			w.code(24)
+		}
+	}
+	if p.ulColor != from.ulColor {
+		if p.ulColor.mode == colorNone {
+			w.code(59)
+		} else {
+			w.color(p.ulColor, 50)
+		}
+	}
+	if p.unknown != from.unknown {
+		w.param(p.unknown)
+	}
+}
+
+// sgrWriter writes the parameters of one SGR sequence as they come. With
+// a nil buf it only counts the bytes the sequence would take.
+type sgrWriter struct {
+	buf  cellWriter
+	n    int
+	size int
+}
+
+func (w *sgrWriter) code(v int) {
+	w.next()
+	w.size += writeInt(w.buf, v)
+}
+
+// underline writes the 4:n parameter selecting underline style n.
+func (w *sgrWriter) underline(n uint8) {
+	w.code(4)
+	w.write(":")
+	w.size += writeInt(w.buf, int(n))
+}
+
+// param writes parameters kept verbatim.
+func (w *sgrWriter) param(s string) {
+	w.next()
+	w.write(s)
+}
+
+// next starts the sequence or separates the next parameter.
+func (w *sgrWriter) next() {
+	if w.n == 0 {
+		w.write("\x1b[")
+	} else {
+		w.write(";")
+	}
+	w.n++
+}
+
+func (w *sgrWriter) write(s string) {
+	w.size += len(s)
+	if w.buf != nil {
+		w.buf.WriteString(s)
+	}
+}
+
+// writeInt writes the decimal digits of v >= 0 to buf, if not nil, without
+// allocating. It returns the number of digits.
+func writeInt(buf cellWriter, v int) int {
+	var digits [20]byte
+	i := len(digits)
+	for {
+		i--
+		digits[i] = byte('0' + v%10)
+		v /= 10
+		if v == 0 || i == 0 {
+			break
+		}
+	}
+	if buf != nil {
+		for _, d := range digits[i:] {
+			buf.WriteByte(d)
+		}
+	}
+	return len(digits) - i
+}
+
+// color writes the parameters selecting c, like [sgrColor.appendCode].
+func (w *sgrWriter) color(c sgrColor, base int) {
+	switch c.mode {
+	case colorBasic:
+		if c.r < 8 {
+			w.code(base + int(c.r))
+		} else {
+			w.code(base + 60 + int(c.r) - 8)
+		}
+	case colorIndex:
+		w.code(base + 8)
+		w.code(5)
+		w.code(int(c.r))
+	case colorRGB:
+		w.code(base + 8)
+		w.code(2)
+		w.code(int(c.r))
+		w.code(int(c.g))
+		w.code(int(c.b))
+	}
+}
+
+// end terminates the sequence, if any parameter was written.
+func (w *sgrWriter) end() {
+	if w.n > 0 {
+		w.write("m")
+	}
+}
@@ -0,0 +1,150 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestSGRApply(t *testing.T) {
+	tests := []struct {
+		name string
+		seqs []string
+		want sgr
+	}{
+		{"basic colors", []string{"\x1b[31;42m"}, sgr{
+			fg: sgrColor{mode: colorBasic, r: 1},
+			bg: sgrColor{mode: colorBasic, r: 2},
+		}},
+		{"bright colors", []string{"\x1b[91m", "\x1b[107m"}, sgr{
+			fg: sgrColor{mode: colorBasic, r: 9},
+			bg: sgrColor{mode: colorBasic, r: 15},
+		}},
+		{"palette and rgb", []string{"\x1b[38;5;208;48;2;1;2;3m"}, sgr{
+			fg: sgrColor{mode: colorIndex, r: 208},
+			bg: sgrColor{mode: colorRGB, r: 1, g: 2, b: 3},
+		}},
+		{"flags", []string{"\x1b[1m", "\x1b[4;9m"}, sgr{flags: flagBold | flagUnderline | flagStrike}},
+		{"flags are cleared", []string{"\x1b[1;2;3m", "\x1b[22m"}, sgr{flags: flagItalic}},
+		{"default colors", []string{"\x1b[31;41m", "\x1b[39;49m"}, sgr{}},
+		{"reset", []string{"\x1b[1;31m", "\x1b[0m"}, sgr{}},
+		{"empty reset", []string{"\x1b[1;31m", "\x1b[m"}, sgr{}},
+		{"colon color", []string{"\x1b[38:2::255:0:0;48:5:17m"}, sgr{
+			fg: sgrColor{mode: colorRGB, r: 255},
+			bg: sgrColor{mode: colorIndex, r: 17},
+		}},
+		{"colon underline", []string{"\x1b[4:1m", "\x1b[1m", "\x1b[4:0m"}, sgr{flags: flagBold}},
+		{"curly underline is one parameter", []string{"\x1b[4:3m"}, sgr{underline: 3}},
+		{"underline styles replace each other", []string{"\x1b[4m", "\x1b[4:3m", "\x1b[4:2m"}, sgr{underline: 2}},
+		{"24 ends a curly underline", []string{"\x1b[4:3m", "\x1b[24m"}, sgr{}},
+		{"4:0 ends a curly underline", []string{"\x1b[4:3m", "\x1b[4:0m"}, sgr{}},
+		{"reset ends a curly underline", []string{"\x1b[4:3;58;5;1m", "\x1b[0m"}, sgr{}},
+		{"plain underline replaces a curly one", []string{"\x1b[4:3m", "\x1b[4m"}, sgr{flags: flagUnderline}},
+		{"underline color arguments", []string{"\x1b[58;2;255;0;0m", "\x1b[31m"}, sgr{
+			fg:      sgrColor{mode: colorBasic, r: 1},
+			ulColor: sgrColor{mode: colorRGB, r: 255},
+		}},
+		{"colon underline color", []string{"\x1b[58:5:9m"}, sgr{ulColor: sgrColor{mode: colorIndex, r: 9}}},
+		{"59 ends the underline color", []string{"\x1b[58;5;1m", "\x1b[59m"}, sgr{}},
+		{"underline colors replace each other", []string{"\x1b[58;5;1m", "\x1b[59m", "\x1b[58;5;2m"}, sgr{
+			ulColor: sgrColor{mode: colorIndex, r: 2},
+		}},
+		{"unknown codes are kept", []string{"\x1b[21;53m", "\x1b[1;73m"}, sgr{flags: flagBold, unknown: "21;53;73"}},
+		{"unknown codes are kept once", []string{"\x1b[53m", "\x1b[53;73m"}, sgr{unknown: "53;73"}},
+		{"reset clears unknown codes", []string{"\x1b[53m", "\x1b[0m"}, sgr{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sgr
+			for _, seq := range tt.seqs {
+				assert.Equal(t, true, got.apply(seq))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("non-SGR sequences are rejected", func(t *testing.T) {
+		var got sgr
+		assert.Equal(t, false, got.apply("\x1b[2K"))
+		assert.Equal(t, false, got.apply("\x1b]8;;https://example.com\x07"))
+		assert.Equal(t, false, got.apply("\x1b[?1m"))
+		assert.Equal(t, false, got.apply("\x1b[>4;2m"))
+		assert.Equal(t, false, got.apply("\x1b[1 m"))
+		assert.Equal(t, sgr{}, got)
+	})
+}
+
+func TestSGRString(t *testing.T) {
+	assert.Equal(t, "", sgr{}.String())
+
+	p := sgr{
+		fg:    sgrColor{mode: colorBasic, r: 9},
+		bg:    sgrColor{mode: colorIndex, r: 17},
+		flags: flagBold | flagItalic,
+	}
+	assert.Equal(t, "\x1b[1;3;91;48;5;17m", p.String())
+
+	p = sgr{fg: sgrColor{mode: colorRGB, r: 255, g: 128}, extra: "\x1b]8;;x\x07"}
+	assert.Equal(t, "\x1b[38;2;255;128;0m\x1b]8;;x\x07", p.String())
+}
+
+func TestSGROver(t *testing.T) {
+	panel := sgr{fg: sgrColor{mode: colorBasic, r: 7}, bg: sgrColor{mode: colorBasic, r: 4}, flags: flagBold}
+
+	t.Run("background is inherited", func(t *testing.T) {
+		text := sgr{fg: sgrColor{mode: colorBasic, r: 1}}
+		want := sgr{fg: sgrColor{mode: colorBasic, r: 1}, bg: sgrColor{mode: colorBasic, r: 4}}
+		assert.Equal(t, want, text.over(panel))
+	})
+
+	t.Run("own background wins", func(t *testing.T) {
+		text := sgr{bg: sgrColor{mode: colorBasic, r: 2}}
+		assert.Equal(t, text, text.over(panel))
+	})
+}
+
+func TestSGRTransition(t *testing.T) {
+	red := sgrColor{mode: colorBasic, r: 1}
+	blue := sgrColor{mode: colorBasic, r: 4}
+	orange := sgrColor{mode: colorIndex, r: 208}
+	blue2 := sgrColor{mode: colorIndex, r: 4}
+
+	tests := []struct {
+		name     string
+		from, to sgr
+		want     string
+	}{
+		{"unchanged", sgr{fg: red}, sgr{fg: red}, ""},
+		{"from unstyled", sgr{}, sgr{fg: red, flags: flagBold}, "\x1b[1;31m"},
+		{"to unstyled", sgr{fg: red, flags: flagBold}, sgr{}, "\x1b[0m"},
+		{"color only", sgr{fg: red, bg: blue}, sgr{fg: orange, bg: blue}, "\x1b[38;5;208m"},
+		{"default color", sgr{fg: red, bg: blue}, sgr{bg: blue}, "\x1b[39m"},
+		{"flag on", sgr{fg: red}, sgr{fg: red, flags: flagBold}, "\x1b[1m"},
+		{"flag off", sgr{fg: red, flags: flagItalic}, sgr{fg: red}, "\x1b[23m"},
+		{"bold off keeps dim", sgr{fg: red, flags: flagBold | flagDim}, sgr{fg: red, flags: flagDim}, "\x1b[22;2m"},
+		{"reset when shorter", sgr{fg: red, flags: flagBold | flagItalic | flagUnderline}, sgr{bg: blue}, "\x1b[0;44m"},
+		{"extra", sgr{}, sgr{extra: "\x1b]8;;x\x07"}, "\x1b]8;;x\x07"},
+		{"unknown added", sgr{fg: red}, sgr{fg: red, unknown: "53"}, "\x1b[53m"},
+		{"unknown removed", sgr{fg: red, unknown: "53"}, sgr{fg: red}, "\x1b[0;31m"},
+		{"curly underline added", sgr{fg: red}, sgr{fg: red, underline: 3}, "\x1b[4:3m"},
+		{"curly underline removed", sgr{fg: red, underline: 3}, sgr{fg: red}, "\x1b[24m"},
+		{"curly to plain underline", sgr{underline: 3}, sgr{flags: flagUnderline}, "\x1b[4m"},
+		{"plain to curly underline", sgr{fg: red, flags: flagUnderline}, sgr{fg: red, underline: 3}, "\x1b[24;4:3m"},
+		{"underline color changed", sgr{fg: red, ulColor: orange}, sgr{fg: red, ulColor: blue2}, "\x1b[58;5;4m"},
+		{"underline color removed", sgr{fg: red, ulColor: orange}, sgr{fg: red}, "\x1b[59m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			tt.to.writeTransition(&buf, tt.from)
+			assert.Equal(t, tt.want, buf.String())
+
+			// The transition leads to the target style.
+			got := tt.from
+			got.apply(buf.String())
+			got.extra = tt.to.extra
+			assert.Equal(t, tt.to, got)
+		})
+	}
+}
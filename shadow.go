@@ -0,0 +1,95 @@
+package tinta
+
+// ShadowStyle describes how a drop shadow is drawn by [BoxStyle.Shadow]
+// and [CanvasStyle.AddShadow].
+type ShadowStyle struct {
+	// Glyph fills the shadow cells, such as '░'. When zero, the shadow
+	// keeps the cells it falls on and darkens their colors instead.
+	Glyph rune
+	// Color styles the shadow glyphs. For a shadow without a glyph, its
+	// background color tints the cells below; black when unset.
+	Color *TextStyle
+}
+
+// Predefined shadow styles.
+var (
+	ShadowLight  = ShadowStyle{Glyph: '░'}
+	ShadowMedium = ShadowStyle{Glyph: '▒'}
+	ShadowDark   = ShadowStyle{Glyph: '▓'}
+	ShadowDim    = ShadowStyle{}
+)
+
+// shadowAlpha is how strongly a shadow without a glyph tints the cells
+// below it.
+const shadowAlpha = 0.5
+
+// AddShadow places a rendered string like [CanvasStyle.Add], together with
+// a drop shadow offset by (dx, dy). The shadow has the shape of the
+// layer's visible cells: every cell except see-through ones and the
+// unstyled spaces padding each row. It is drawn just below the layer, so
+// it never covers the layer's own content.
+func (c *CanvasStyle) AddShadow(s string, x, y, dx, dy int, shadow ShadowStyle) *CanvasStyle {
+	grid := c.parse(s)
+
+	style := textSGR(shadow.Color)
+	fill := cell{r: shadow.Glyph, style: style}
+	if shadow.Glyph == 0 {
+		if style.bg.mode == colorNone {
+			style.bg = sgrColor{mode: colorBasic}
+		}
+		fill = cell{r: ' ', style: sgr{bg: style.bg}}
+	}
+
+	prepare := func(grid [][]cell) { castShadow(grid, dx, dy, fill) }
+	shape := c.parse(s)
+	prepare(shape)
+
+	cp := c.addLayer(layer{
+		grid:    shape,
+		x:       x + dx,
+		y:       y + dy,
+		z:       c.nextZ,
+		blended: shadow.Glyph == 0,
+		alpha:   shadowAlpha,
+		prepare: prepare,
+	})
+	cp = cp.addLayer(layer{grid: grid, x: x, y: y, z: cp.nextZ})
+	cp.lastAdd--
+	return cp
+}
+
+// castShadow turns grid, in place, into the shadow it casts when offset by
+// (dx, dy): its visible cells become fill and the rest see-through.
+func castShadow(grid [][]cell, dx, dy int, fill cell) {
+	visible := make([][]bool, len(grid))
+	for rowIdx, row := range grid {
+		visible[rowIdx] = make([]bool, len(row))
+		first, last := len(row), -1
+		for i, cl := range row {
+			if cl.r != ' ' || !cl.style.isZero() {
+				if i < first {
+					first = i
+				}
+				last = i
+			}
+		}
+		for i, cl := range row {
+			visible[rowIdx][i] = i >= first && i <= last && !cl.clear
+		}
+	}
+	covered := func(row, col int) bool {
+		return row >= 0 && row < len(visible) && col >= 0 && col < len(visible[row]) && visible[row][col]
+	}
+
+	// Shadow cells under the layer itself are left out, so that the
+	// layer does not inherit the shadow's colors.
+	for rowIdx, row := range visible {
+		for i, v := range row {
+			if v && !covered(rowIdx+dy, i+dx) {
+				grid[rowIdx][i] = fill
+			} else {
+				grid[rowIdx][i] = cell{r: ' ', clear: true}
+			}
+		}
+	}
+}
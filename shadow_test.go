@@ -0,0 +1,55 @@
+package tinta
+
+import (
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasAddShadow(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	box := Box().String("x")
+
+	t.Run("glyph shadow", func(t *testing.T) {
+		got := Canvas().AddShadow(box, 0, 0, 1, 1, ShadowLight).String()
+		assert.Equal(t, "┌─┐\n│x│░\n└─┘░\n ░░░", got)
+	})
+
+	t.Run("shadow never covers content", func(t *testing.T) {
+		got := Canvas().AddShadow(box, 0, 0, -1, 0, ShadowDark).String()
+		assert.Equal(t, "▓┌─┐\n▓│x│\n▓└─┘", got)
+	})
+
+	t.Run("shadow follows the visible shape", func(t *testing.T) {
+		got := Canvas().AddShadow("ab\n  c", 0, 0, 1, 0, ShadowMedium).String()
+		assert.Equal(t, "ab▒\n  c▒", got)
+	})
+
+	t.Run("shadow falls on lower layers", func(t *testing.T) {
+		got := Canvas().Add("....\n....", 0, 0).AddShadow("ab", 0, 0, 1, 1, ShadowMedium).String()
+		assert.Equal(t, "ab..\n.▒▒.", got)
+	})
+
+	t.Run("dim shadow keeps the cells below", func(t *testing.T) {
+		got := Canvas().Add("....\n....", 0, 0).AddShadow("ab", 0, 0, 1, 1, ShadowDim).String()
+		assert.Equal(t, "ab..\n....", got)
+	})
+
+	t.Run("dim shadow darkens with colors", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		row := Text().OnWhite().String("   ")
+		below := row + "\n" + row
+		got := Canvas().Add(below, 0, 0).AddShadow("ab", 0, 0, 1, 1, ShadowDim).String()
+		assert.Equal(t, "\x1b[47mab \x1b[0m\n\x1b[47m \x1b[38;2;115;115;115;48;2;115;115;115m  \x1b[0m", got)
+	})
+
+	t.Run("colored glyphs", func(t *testing.T) {
+		ForceColors(true)
+		defer ForceColors(false)
+		got := Canvas().AddShadow("a", 0, 0, 1, 0, ShadowStyle{Glyph: '#', Color: Text().BrightBlack()}).String()
+		assert.Equal(t, "a\x1b[90m#\x1b[0m", got)
+	})
+}
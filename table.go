@@ -0,0 +1,582 @@
+package tinta
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TableCell is a table cell that covers more than one column. Create one
+// with [SpanCell] and pass it to Headers, Row or Footer in place of a
+// string.
+type TableCell struct {
+	Content string
+	Span    int
+}
+
+// SpanCell returns a [TableCell] holding content that covers span columns.
+// Spans below 1 are treated as 1.
+func SpanCell(content string, span int) TableCell {
+	if span < 1 {
+		span = 1
+	}
+	return TableCell{Content: content, Span: span}
+}
+
+type tableColumn struct {
+	align    Align
+	width    int
+	minWidth int
+	maxWidth int
+}
+
+// TableStyle holds the configuration and data of a bordered table. Create
+// one with [Table], add data with Headers, Row and Footer, and configure
+// columns, borders and styles with the other methods. Cell contents are
+// measured without ANSI escapes, so pre-styled strings line up.
+//
+// All methods return a new TableStyle to preserve immutability.
+type TableStyle struct {
+	header      []TableCell
+	rows        [][]TableCell
+	footer      []TableCell
+	columns     map[int]tableColumn
+	border      Border
+	padding     int
+	width       int
+	truncate    bool
+	separators  bool
+	headerStyle *TextStyle
+	footerStyle *TextStyle
+	zebraStyle  *TextStyle
+	borderStyle *TextStyle
+}
+
+// Table returns a new empty [TableStyle] with a simple border and one
+// space of padding on each side of every cell.
+func Table() *TableStyle {
+	return &TableStyle{border: BorderSimple, padding: 1}
+}
+
+func copyTable(t *TableStyle) *TableStyle {
+	cp := *t
+	if len(t.rows) > 0 {
+		cp.rows = make([][]TableCell, len(t.rows))
+		copy(cp.rows, t.rows)
+	}
+	if len(t.columns) > 0 {
+		cp.columns = make(map[int]tableColumn, len(t.columns))
+		for k, v := range t.columns {
+			cp.columns[k] = v
+		}
+	}
+	return &cp
+}
+
+func toTableCells(cells []any) []TableCell {
+	out := make([]TableCell, len(cells))
+	for i, c := range cells {
+		switch v := c.(type) {
+		case TableCell:
+			if v.Span < 1 {
+				v.Span = 1
+			}
+			out[i] = v
+		case string:
+			out[i] = TableCell{Content: v, Span: 1}
+		default:
+			out[i] = TableCell{Content: fmt.Sprint(v), Span: 1}
+		}
+	}
+	return out
+}
+
+// Headers sets the header row. Cells may be strings, [TableCell] values
+// from [SpanCell], or any other value, which is formatted with fmt.Sprint.
+func (t *TableStyle) Headers(cells ...any) *TableStyle {
+	cp := copyTable(t)
+	cp.header = toTableCells(cells)
+	return cp
+}
+
+// Row appends a body row. Cells follow the same rules as in Headers.
+// Rows with fewer cells than the table has columns are padded with
+// empty cells.
+func (t *TableStyle) Row(cells ...any) *TableStyle {
+	cp := copyTable(t)
+	cp.rows = append(cp.rows, toTableCells(cells))
+	return cp
+}
+
+// Rows appends several body rows of plain strings.
+func (t *TableStyle) Rows(rows ...[]string) *TableStyle {
+	cp := copyTable(t)
+	for _, row := range rows {
+		cells := make([]TableCell, len(row))
+		for i, c := range row {
+			cells[i] = TableCell{Content: c, Span: 1}
+		}
+		cp.rows = append(cp.rows, cells)
+	}
+	return cp
+}
+
+// Footer sets the footer row, drawn below the body and separated from it
+// by a border line. Cells follow the same rules as in Headers.
+func (t *TableStyle) Footer(cells ...any) *TableStyle {
+	cp := copyTable(t)
+	cp.footer = toTableCells(cells)
+	return cp
+}
+
+func (t *TableStyle) withColumn(col int, fn func(*tableColumn)) *TableStyle {
+	if col < 0 {
+		return t
+	}
+	cp := copyTable(t)
+	if cp.columns == nil {
+		cp.columns = make(map[int]tableColumn)
+	}
+	c := cp.columns[col]
+	fn(&c)
+	cp.columns[col] = c
+	return cp
+}
+
+// ColumnAlign sets the alignment of column col (0-based) for every row,
+// including headers and footer. Cells spanning several columns use the
+// alignment of their first column.
+func (t *TableStyle) ColumnAlign(col int, align Align) *TableStyle {
+	return t.withColumn(col, func(c *tableColumn) { c.align = align })
+}
+
+// ColumnWidth fixes the content width of column col, excluding padding.
+// Zero restores automatic sizing.
+func (t *TableStyle) ColumnWidth(col, n int) *TableStyle {
+	return t.withColumn(col, func(c *tableColumn) { c.width = n })
+}
+
+// ColumnMinWidth sets the smallest content width of column col.
+func (t *TableStyle) ColumnMinWidth(col, n int) *TableStyle {
+	return t.withColumn(col, func(c *tableColumn) { c.minWidth = n })
+}
+
+// ColumnMaxWidth sets the largest content width of column col. Zero
+// means no limit.
+func (t *TableStyle) ColumnMaxWidth(col, n int) *TableStyle {
+	return t.withColumn(col, func(c *tableColumn) { c.maxWidth = n })
+}
+
+// Width limits the total width of the table, borders included. The widest
+// columns are narrowed, down to their minimum width, until the table fits.
+// Zero means no limit.
+func (t *TableStyle) Width(n int) *TableStyle {
+	cp := copyTable(t)
+	cp.width = n
+	return cp
+}
+
+// Truncate cuts cell content that does not fit its column and marks the
+// cut with "…", instead of word-wrapping it onto more lines.
+func (t *TableStyle) Truncate() *TableStyle {
+	cp := copyTable(t)
+	cp.truncate = true
+	return cp
+}
+
+// Border sets the border glyphs. Lines between cells use the border's
+// inner and junction glyphs, so mixed presets such as [BorderDoubleSingle]
+// draw a double frame around single inner lines.
+func (t *TableStyle) Border(border Border) *TableStyle {
+	cp := copyTable(t)
+	cp.border = border
+	return cp
+}
+
+// Padding sets the number of spaces on the left and right of every cell.
+func (t *TableStyle) Padding(n int) *TableStyle {
+	cp := copyTable(t)
+	cp.padding = n
+	return cp
+}
+
+// RowSeparators draws a border line between body rows.
+func (t *TableStyle) RowSeparators() *TableStyle {
+	cp := copyTable(t)
+	cp.separators = true
+	return cp
+}
+
+// HeaderStyle sets the style applied to header cells, padding included.
+func (t *TableStyle) HeaderStyle(s *TextStyle) *TableStyle {
+	cp := copyTable(t)
+	cp.headerStyle = s
+	return cp
+}
+
+// FooterStyle sets the style applied to footer cells, padding included.
+func (t *TableStyle) FooterStyle(s *TextStyle) *TableStyle {
+	cp := copyTable(t)
+	cp.footerStyle = s
+	return cp
+}
+
+// ZebraStyle sets the style applied to every second body row, starting
+// with the second one.
+func (t *TableStyle) ZebraStyle(s *TextStyle) *TableStyle {
+	cp := copyTable(t)
+	cp.zebraStyle = s
+	return cp
+}
+
+// BorderStyle sets the style applied to border glyphs.
+func (t *TableStyle) BorderStyle(s *TextStyle) *TableStyle {
+	cp := copyTable(t)
+	cp.borderStyle = s
+	return cp
+}
+
+// String renders the table and returns the result.
+func (t *TableStyle) String() string {
+	return t.render()
+}
+
+// Print renders the table and writes it to the default output.
+func (t *TableStyle) Print() {
+	_, _ = fmt.Fprint(getOutput(), t.render())
+}
+
+// Println renders the table and writes it followed by a newline to the
+// default output.
+func (t *TableStyle) Println() {
+	_, _ = fmt.Fprintln(getOutput(), t.render())
+}
+
+// Fprint renders the table and writes it to w.
+func (t *TableStyle) Fprint(w io.Writer) (int, error) {
+	return fmt.Fprint(w, t.render())
+}
+
+// Fprintln renders the table and writes it followed by a newline to w.
+func (t *TableStyle) Fprintln(w io.Writer) (int, error) {
+	return fmt.Fprintln(w, t.render())
+}
+
+func styleWith(s *TextStyle, text string) string {
+	if s == nil {
+		return text
+	}
+	return s.render(text)
+}
+
+// styleUnder renders text with s as a base style that also shows through
+// resets inside pre-styled text, so colored cell content keeps the zebra,
+// header or footer background.
+func styleUnder(s *TextStyle, text string) string {
+	base := textSGR(s)
+	if base.isZero() {
+		return text
+	}
+	cells := parseLine(text)
+	for i := range cells {
+		cells[i].style = cells[i].style.under(base)
+	}
+	return encodeCells(cells)
+}
+
+// tableRow is a row of cells normalized to cover every column.
+type tableRow struct {
+	cells []TableCell
+	style *TextStyle
+}
+
+func (t *TableStyle) layoutRows() (header, body, footer []tableRow, nCols int) {
+	all := make([][]TableCell, 0, len(t.rows)+2)
+	if len(t.header) > 0 {
+		all = append(all, t.header)
+	}
+	all = append(all, t.rows...)
+	if len(t.footer) > 0 {
+		all = append(all, t.footer)
+	}
+	for _, cells := range all {
+		n := 0
+		for _, c := range cells {
+			n += c.Span
+		}
+		if n > nCols {
+			nCols = n
+		}
+	}
+
+	fill := func(cells []TableCell, style *TextStyle) tableRow {
+		n := 0
+		out := make([]TableCell, 0, len(cells))
+		for _, c := range cells {
+			if n+c.Span > nCols {
+				c.Span = nCols - n
+			}
+			c.Content = expandTabs(c.Content, 0)
+			out = append(out, c)
+			n += c.Span
+		}
+		for ; n < nCols; n++ {
+			out = append(out, TableCell{Span: 1})
+		}
+		return tableRow{cells: out, style: style}
+	}
+
+	if len(t.header) > 0 {
+		header = []tableRow{fill(t.header, t.headerStyle)}
+	}
+	for i, cells := range t.rows {
+		var style *TextStyle
+		if i%2 == 1 {
+			style = t.zebraStyle
+		}
+		body = append(body, fill(cells, style))
+	}
+	if len(t.footer) > 0 {
+		footer = []tableRow{fill(t.footer, t.footerStyle)}
+	}
+	return header, body, footer, nCols
+}
+
+func (t *TableStyle) columnWidths(rows []tableRow, nCols int) []int {
+	pad := t.padding
+	if pad < 0 {
+		pad = 0
+	}
+	widths := make([]int, nCols)
+	for _, row := range rows {
+		col := 0
+		for _, c := range row.cells {
+			if c.Span == 1 {
+				if w := blockWidth(strings.Split(c.Content, "\n")); w > widths[col] {
+					widths[col] = w
+				}
+			}
+			col += c.Span
+		}
+	}
+	for _, row := range rows {
+		col := 0
+		for _, c := range row.cells {
+			if c.Span > 1 {
+				need := blockWidth(strings.Split(c.Content, "\n"))
+				have := spanSize(widths, col, c.Span, 2*pad+1)
+				if need > have {
+					widths[col+c.Span-1] += need - have
+				}
+			}
+			col += c.Span
+		}
+	}
+
+	for i := range widths {
+		c := t.columns[i]
+		if c.width > 0 {
+			widths[i] = c.width
+			continue
+		}
+		if c.maxWidth > 0 && widths[i] > c.maxWidth {
+			widths[i] = c.maxWidth
+		}
+		if widths[i] < c.minWidth {
+			widths[i] = c.minWidth
+		}
+	}
+
+	if t.width > 0 {
+		total := 1 + nCols*(2*pad+1)
+		for _, w := range widths {
+			total += w
+		}
+		for total > t.width {
+			widest := -1
+			for i, w := range widths {
+				c := t.columns[i]
+				limit := c.minWidth
+				if limit < 1 {
+					limit = 1
+				}
+				if c.width > 0 || w <= limit {
+					continue
+				}
+				if widest < 0 || w > widths[widest] {
+					widest = i
+				}
+			}
+			if widest < 0 {
+				break
+			}
+			widths[widest]--
+			total--
+		}
+	}
+	return widths
+}
+
+func (t *TableStyle) render() string {
+	header, body, footer, nCols := t.layoutRows()
+	if nCols == 0 {
+		return ""
+	}
+	pad := t.padding
+	if pad < 0 {
+		pad = 0
+	}
+
+	all := make([]tableRow, 0, len(header)+len(body)+len(footer))
+	all = append(all, header...)
+	all = append(all, body...)
+	all = append(all, footer...)
+	widths := t.columnWidths(all, nCols)
+
+	type entry struct {
+		row       *tableRow
+		ruleAbove bool
+	}
+	entries := make([]entry, 0, len(all))
+	for i := range header {
+		entries = append(entries, entry{row: &header[i]})
+	}
+	for i := range body {
+		rule := i > 0 && t.separators || i == 0 && len(header) > 0
+		entries = append(entries, entry{row: &body[i], ruleAbove: rule})
+	}
+	for i := range footer {
+		entries = append(entries, entry{row: &footer[i], ruleAbove: len(entries) > 0})
+	}
+
+	lines := []string{t.rule(nil, entries[0].row, widths, pad)}
+	var prev *tableRow
+	for _, e := range entries {
+		if e.ruleAbove {
+			lines = append(lines, t.rule(prev, e.row, widths, pad))
+		}
+		lines = append(lines, t.rowLines(*e.row, widths, pad)...)
+		prev = e.row
+	}
+	lines = append(lines, t.rule(prev, nil, widths, pad))
+
+	return strings.Join(lines, "\n")
+}
+
+// boundaries reports which column boundaries (0..n) a row draws a
+// vertical line on.
+func (r *tableRow) boundaries(nCols int) []bool {
+	b := make([]bool, nCols+1)
+	b[0], b[nCols] = true, true
+	col := 0
+	for _, c := range r.cells {
+		b[col] = true
+		col += c.Span
+	}
+	return b
+}
+
+// rule draws a horizontal border line between the rows above and below,
+// either of which may be nil at the outer edges.
+func (t *TableStyle) rule(above, below *tableRow, widths []int, pad int) string {
+	nCols := len(widths)
+	up := make([]bool, nCols+1)
+	down := make([]bool, nCols+1)
+	if above != nil {
+		up = above.boundaries(nCols)
+	}
+	if below != nil {
+		down = below.boundaries(nCols)
+	}
+
+	edge := t.border.innerHorizontal()
+	switch {
+	case above == nil:
+		edge = t.border.Top
+	case below == nil:
+		edge = t.border.Bottom
+	}
+
+	var b strings.Builder
+	for i := 0; i <= nCols; i++ {
+		lines := edgeSet(up[i], down[i], i > 0, i < nCols)
+		frame := edgeSet(above == nil, below == nil, i == 0, i == nCols)
+		b.WriteString(t.border.junction(lines, frame))
+		if i < nCols {
+			b.WriteString(strings.Repeat(edge, widths[i]+2*pad))
+		}
+	}
+	return styleWith(t.borderStyle, b.String())
+}
+
+// rowLines renders the text lines of one row, including vertical borders.
+func (t *TableStyle) rowLines(row tableRow, widths []int, pad int) []string {
+	type block struct {
+		lines []string
+		width int
+		align Align
+	}
+	blocks := make([]block, len(row.cells))
+	height := 1
+	col := 0
+	for i, c := range row.cells {
+		w := spanSize(widths, col, c.Span, 2*pad+1)
+		var lines []string
+		for _, line := range strings.Split(c.Content, "\n") {
+			if t.truncate {
+				lines = append(lines, truncateEllipsis(line, w))
+			} else {
+				lines = append(lines, wrapLine(line, w)...)
+			}
+		}
+		if len(lines) > height {
+			height = len(lines)
+		}
+		blocks[i] = block{lines: lines, width: w, align: t.columns[col].align}
+		col += c.Span
+	}
+
+	left := styleWith(t.borderStyle, t.border.Left)
+	right := styleWith(t.borderStyle, t.border.Right)
+	inner := styleWith(t.borderStyle, t.border.innerVertical())
+	padding := strings.Repeat(" ", pad)
+
+	out := make([]string, height)
+	for y := range out {
+		var b strings.Builder
+		b.WriteString(left)
+		for i, bl := range blocks {
+			if i > 0 {
+				b.WriteString(inner)
+			}
+			text := ""
+			if y < len(bl.lines) {
+				text = bl.lines[y]
+			}
+			if w := visibleWidth(text); w > bl.width {
+				text = truncateLine(text, bl.width)
+			}
+			free := bl.width - visibleWidth(text)
+			lp := alignOffset(bl.align, free)
+			cell := padding + strings.Repeat(" ", lp) + text + strings.Repeat(" ", free-lp) + padding
+			b.WriteString(styleUnder(row.style, cell))
+		}
+		b.WriteString(right)
+		out[y] = b.String()
+	}
+	return out
+}
+
+// truncateEllipsis cuts line to width visible columns, replacing the last
+// visible column with "…" when anything was cut.
+func truncateEllipsis(line string, width int) string {
+	if visibleWidth(line) <= width {
+		return line
+	}
+	if width <= 0 {
+		return ""
+	}
+	cells := parseLine(line)[:width]
+	cells[width-1].r = '…'
+	return encodeCells(cells)
+}
@@ -0,0 +1,214 @@
+package tinta
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestTableBasic(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("empty table", func(t *testing.T) {
+		assert.Equal(t, "", Table().String())
+	})
+
+	t.Run("body only", func(t *testing.T) {
+		got := Table().Row("a", "b").String()
+		assert.Equal(t, "┌───┬───┐\n│ a │ b │\n└───┴───┘", got)
+	})
+
+	t.Run("headers and rows", func(t *testing.T) {
+		got := Table().Headers("Name", "Age").Row("ana", 31).Row("bo", 7).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌──────┬─────┐", lines[0])
+		assert.Equal(t, "│ Name │ Age │", lines[1])
+		assert.Equal(t, "├──────┼─────┤", lines[2])
+		assert.Equal(t, "│ ana  │ 31  │", lines[3])
+		assert.Equal(t, "│ bo   │ 7   │", lines[4])
+		assert.Equal(t, "└──────┴─────┘", lines[5])
+	})
+
+	t.Run("Rows appends string rows", func(t *testing.T) {
+		got := Table().Rows([]string{"a"}, []string{"b"}).String()
+		assert.Equal(t, "┌───┐\n│ a │\n│ b │\n└───┘", got)
+	})
+
+	t.Run("short rows are padded", func(t *testing.T) {
+		got := Table().Row("a", "b").Row("c").String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│ c │   │", lines[2])
+	})
+
+	t.Run("footer is separated", func(t *testing.T) {
+		got := Table().Row("a", "1").Footer("sum", "1").String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│ a   │ 1 │", lines[1])
+		assert.Equal(t, "├─────┼───┤", lines[2])
+		assert.Equal(t, "│ sum │ 1 │", lines[3])
+	})
+
+	t.Run("row separators", func(t *testing.T) {
+		got := Table().Row("a").Row("b").RowSeparators().String()
+		assert.Equal(t, "┌───┐\n│ a │\n├───┤\n│ b │\n└───┘", got)
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		got := Table().Padding(0).Row("a", "b").String()
+		assert.Equal(t, "┌─┬─┐\n│a│b│\n└─┴─┘", got)
+	})
+}
+
+func TestTableColumns(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("alignment", func(t *testing.T) {
+		got := Table().Row("left", "1").Row("x", "100").
+			ColumnAlign(0, AlignCenter).ColumnAlign(1, AlignRight).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│ left │   1 │", lines[1])
+		assert.Equal(t, "│  x   │ 100 │", lines[2])
+	})
+
+	t.Run("fixed width wraps", func(t *testing.T) {
+		got := Table().Row("aaa bbb").ColumnWidth(0, 4).String()
+		assert.Equal(t, "┌──────┐\n│ aaa  │\n│ bbb  │\n└──────┘", got)
+	})
+
+	t.Run("fixed width truncates with ellipsis", func(t *testing.T) {
+		got := Table().Row("abcdef").ColumnWidth(0, 4).Truncate().String()
+		assert.Equal(t, "┌──────┐\n│ abc… │\n└──────┘", got)
+	})
+
+	t.Run("min and max width", func(t *testing.T) {
+		got := Table().Row("a", "abcdef").ColumnMinWidth(0, 3).ColumnMaxWidth(1, 3).Truncate().String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│ a   │ ab… │", lines[1])
+	})
+
+	t.Run("table width shrinks widest column", func(t *testing.T) {
+		got := Table().Row("ab", "the quick fox").Width(15).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, 15, visibleWidth(lines[0]))
+		assert.Equal(t, "│ ab │ the    │", lines[1])
+		assert.Equal(t, "│    │ quick  │", lines[2])
+		assert.Equal(t, "│    │ fox    │", lines[3])
+	})
+}
+
+func TestTableSpans(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	t.Run("span merges columns with junctions", func(t *testing.T) {
+		got := Table().Headers("a", "b", "c").Row(SpanCell("wide", 2), "z").String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌───┬───┬───┐", lines[0])
+		assert.Equal(t, "├───┴───┼───┤", lines[2])
+		assert.Equal(t, "│ wide  │ z │", lines[3])
+		assert.Equal(t, "└───────┴───┘", lines[4])
+	})
+
+	t.Run("wide span grows the last spanned column", func(t *testing.T) {
+		got := Table().Row("a", "b").Row(SpanCell("long text", 2)).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "┌───┬───────┐", lines[0])
+		assert.Equal(t, "│ long text │", lines[2])
+	})
+
+	t.Run("span below one is one", func(t *testing.T) {
+		assert.Equal(t, 1, SpanCell("x", 0).Span)
+	})
+}
+
+func TestTableBorders(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	tests := []struct {
+		name   string
+		border Border
+		want   string
+	}{
+		{"double", BorderDouble, "╔═══╦═══╗\n║ a ║ b ║\n╠═══╬═══╣\n║ c ║ d ║\n╚═══╩═══╝"},
+		{"heavy", BorderHeavy, "┏━━━┳━━━┓\n┃ a ┃ b ┃\n┣━━━╋━━━┫\n┃ c ┃ d ┃\n┗━━━┻━━━┛"},
+		{"rounded", BorderRounded, "╭───┬───╮\n│ a │ b │\n├───┼───┤\n│ c │ d │\n╰───┴───╯"},
+		{"ascii", BorderASCII, "+---+---+\n| a | b |\n+---+---+\n| c | d |\n+---+---+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Table().Border(tt.border).Headers("a", "b").Row("c", "d").String()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTableStyles(t *testing.T) {
+	ForceColors(true)
+
+	t.Run("header and zebra styles", func(t *testing.T) {
+		got := Table().Headers("h").Row("a").Row("b").
+			HeaderStyle(Text().Bold()).ZebraStyle(Text().OnBlue()).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│\x1b[1m h \x1b[0m│", lines[1])
+		assert.Equal(t, "│ a │", lines[3])
+		assert.Equal(t, "│\x1b[44m b \x1b[0m│", lines[4])
+	})
+
+	t.Run("border style", func(t *testing.T) {
+		got := Table().Row("a").BorderStyle(Text().Red()).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "\x1b[31m┌───┐\x1b[0m", lines[0])
+	})
+
+	t.Run("styled cells keep alignment", func(t *testing.T) {
+		got := Table().Row(Text().Red().String("ok"), "x").Row("fail", "y").String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, visibleWidth(lines[1]), visibleWidth(lines[2]))
+	})
+
+	t.Run("zebra background survives colored content", func(t *testing.T) {
+		got := Table().Headers("a", "b").Row("p", "q").Row(Text().Red().String("x")+" tail", "y").
+			ZebraStyle(Text().OnBlue()).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│\x1b[44m \x1b[31mx\x1b[39m tail \x1b[0m│\x1b[44m y \x1b[0m│", lines[4])
+	})
+
+	t.Run("header style survives colored content", func(t *testing.T) {
+		got := Table().Headers(Text().Red().String("h") + "!").Row("a").
+			HeaderStyle(Text().Bold().OnBlue()).String()
+		lines := strings.Split(got, "\n")
+		assert.Equal(t, "│\x1b[1;44m \x1b[31mh\x1b[39m! \x1b[0m│", lines[1])
+	})
+}
+
+func TestTableOutput(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	var buf bytes.Buffer
+	_, err := Table().Row("a").Fprintln(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "┌───┐\n│ a │\n└───┘\n", buf.String())
+}
+
+func TestTableImmutability(t *testing.T) {
+	base := Table().Row("a")
+	_ = base.Row("b").ColumnAlign(0, AlignRight).Width(10).Truncate()
+	assert.Equal(t, 1, len(base.rows))
+	assert.Equal(t, 0, len(base.columns))
+	assert.Equal(t, 0, base.width)
+	assert.Equal(t, false, base.truncate)
+}
+
+func TestTableMixedBorder(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	got := Table().Border(BorderDoubleSingle).Headers("a", "b").Row("c", "d").String()
+	assert.Equal(t, "╔═══╤═══╗\n║ a │ b ║\n╟───┼───╢\n║ c │ d ║\n╚═══╧═══╝", got)
+}
@@ -0,0 +1,41 @@
+package tinta
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+const (
+	defaultColumns = 80
+	defaultRows    = 24
+)
+
+// TerminalSize reports the size of the terminal attached to w in columns
+// and rows. It queries the terminal directly when w is a terminal file,
+// then falls back to the COLUMNS and LINES environment variables, and
+// finally to 80x24. Each dimension falls back independently.
+func TerminalSize(w io.Writer) (cols, rows int) {
+	return terminalSize(w, os.Getenv)
+}
+
+func terminalSize(w io.Writer, getenv func(string) string) (cols, rows int) {
+	if f, ok := w.(*os.File); ok && f != nil && isTerminal(f) {
+		cols, rows = querySize(f)
+	}
+	if cols <= 0 {
+		cols = envSize(getenv("COLUMNS"), defaultColumns)
+	}
+	if rows <= 0 {
+		rows = envSize(getenv("LINES"), defaultRows)
+	}
+	return cols, rows
+}
+
+func envSize(v string, fallback int) int {
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package tinta
+
+import "os"
+
+func querySize(_ *os.File) (cols, rows int) {
+	return 0, 0
+}
@@ -0,0 +1,49 @@
+package tinta
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestTerminalSize(t *testing.T) {
+	t.Run("non-terminal writer falls back to env", func(t *testing.T) {
+		cols, rows := terminalSize(&bytes.Buffer{}, fakeEnv(map[string]string{
+			"COLUMNS": "132",
+			"LINES":   "43",
+		}))
+		assert.Equal(t, 132, cols)
+		assert.Equal(t, 43, rows)
+	})
+
+	t.Run("empty env falls back to defaults", func(t *testing.T) {
+		cols, rows := terminalSize(&bytes.Buffer{}, fakeEnv(map[string]string{}))
+		assert.Equal(t, 80, cols)
+		assert.Equal(t, 24, rows)
+	})
+
+	t.Run("invalid env values fall back independently", func(t *testing.T) {
+		cols, rows := terminalSize(&bytes.Buffer{}, fakeEnv(map[string]string{
+			"COLUMNS": "wide",
+			"LINES":   "50",
+		}))
+		assert.Equal(t, 80, cols)
+		assert.Equal(t, 50, rows)
+	})
+
+	t.Run("non-positive env values are ignored", func(t *testing.T) {
+		cols, rows := terminalSize(&bytes.Buffer{}, fakeEnv(map[string]string{
+			"COLUMNS": "0",
+			"LINES":   "-3",
+		}))
+		assert.Equal(t, 80, cols)
+		assert.Equal(t, 24, rows)
+	})
+
+	t.Run("nil writer falls back", func(t *testing.T) {
+		cols, rows := terminalSize(nil, fakeEnv(map[string]string{"COLUMNS": "100"}))
+		assert.Equal(t, 100, cols)
+		assert.Equal(t, 24, rows)
+	})
+}
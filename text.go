@@ -13,6 +13,7 @@ const (
 	cDim       = "2"
 	cItalic    = "3"
 	cUnderline = "4"
+	cBlink     = "5"
 	cInvert    = "7"
 	cHidden    = "8"
 	cStrike    = "9"
@@ -20,9 +20,16 @@
 //
 // Use [Canvas] to composite multiple rendered strings into layered 2D output:
 //
+//	back := tinta.Box().Border(tinta.BorderRounded).PaddingX(3).String("world")
 //	front := tinta.Box().Border(tinta.BorderHeavy).PaddingX(3).String("hello")
-//	shadow := tinta.Box().Border(tinta.BorderRounded).PaddingX(3).String("hello")
-//	tinta.Canvas().Add(shadow, 1, 1).Add(front, 0, 0).String()
+//	tinta.Canvas().Add(back, 4, 1).AddShadow(front, 0, 0, 1, 1, tinta.ShadowDim).String()
+//
+// # Layout
+//
+// Use [Row] and [Column] for flexbox-style layouts, or [JoinHorizontal]
+// and [JoinVertical] to place rendered blocks next to each other:
+//
+//	tinta.Row(tinta.BoxItem(tinta.Box(), "menu"), tinta.Item("body").Grow(1)).Render(80, 0)
 //
 // The default output is [os.Stdout]. Change it with [SetOutput].
 // Color support is detected automatically. Override with [ForceColors].
@@ -0,0 +1,308 @@
+package tinta
+
+// transform is a flip, rotation or scaling applied to a layer.
+type transform struct {
+	kind   int
+	turns  int
+	sx, sy int
+}
+
+const (
+	transformFlipH = iota
+	transformFlipV
+	transformRotate
+	transformScale
+)
+
+// Runes that change when mirrored or rotated. Box-drawing glyphs are
+// remapped through their arms instead, see transformRune.
+var (
+	mirrorH = map[rune]rune{
+		'/': '\\', '\\': '/', '(': ')', ')': '(', '[': ']', ']': '[',
+		'{': '}', '}': '{', '<': '>', '>': '<', '╱': '╲', '╲': '╱',
+		'▌': '▐', '▐': '▌', '▘': '▝', '▝': '▘', '▖': '▗', '▗': '▖',
+		'▙': '▟', '▟': '▙', '▛': '▜', '▜': '▛', '▚': '▞', '▞': '▚',
+	}
+	mirrorV = map[rune]rune{
+		'/': '\\', '\\': '/', '╱': '╲', '╲': '╱', '▀': '▄', '▄': '▀',
+		'▘': '▖', '▖': '▘', '▝': '▗', '▗': '▝', '▙': '▛', '▛': '▙',
+		'▟': '▜', '▜': '▟', '▚': '▞', '▞': '▚',
+	}
+	// rotateCW maps runes to their quarter turn clockwise.
+	rotateCW = map[rune]rune{
+		'-': '|', '|': '-', '/': '\\', '\\': '/', '╱': '╲', '╲': '╱',
+		'▀': '▐', '▐': '▄', '▄': '▌', '▌': '▀', '▘': '▝', '▝': '▗',
+		'▗': '▖', '▖': '▘', '▛': '▜', '▜': '▟', '▟': '▙', '▙': '▛',
+		'▚': '▞', '▞': '▚',
+		'┄': '┆', '┆': '┄', '┅': '┇', '┇': '┅', '┈': '┊', '┊': '┈',
+		'┉': '┋', '┋': '┉', '╌': '╎', '╎': '╌', '╍': '╏', '╏': '╍',
+	}
+)
+
+func flipArmsH(e lineEdges) lineEdges {
+	e[armLeft], e[armRight] = e[armRight], e[armLeft]
+	return e
+}
+
+func flipArmsV(e lineEdges) lineEdges {
+	e[armUp], e[armDown] = e[armDown], e[armUp]
+	return e
+}
+
+func rotateArms(e lineEdges) lineEdges {
+	return lineEdges{
+		armUp:    e[armLeft],
+		armRight: e[armUp],
+		armDown:  e[armRight],
+		armLeft:  e[armDown],
+	}
+}
+
+// transformRune returns r after a transform whose effect on glyph arms
+// is arms, looking up other runes in table.
+func transformRune(r rune, table map[rune]rune, arms func(lineEdges) lineEdges) rune {
+	if t, ok := table[r]; ok {
+		return t
+	}
+	e, ok := edgesOf(r)
+	if !ok {
+		return r
+	}
+	moved := arms(e)
+	if moved == e {
+		return r
+	}
+	g, ok := glyphFor(moved)
+	if !ok {
+		return r
+	}
+	for _, rounded := range roundedCorners {
+		if r == rounded {
+			if rg, ok := roundedCorners[g]; ok {
+				return rg
+			}
+		}
+	}
+	return g
+}
+
+// FlipH mirrors the layers added by the previous call left to right.
+// Box-drawing glyphs and runes such as slashes, brackets and half blocks
+// are swapped for their mirrored counterparts.
+func (c *CanvasStyle) FlipH() *CanvasStyle {
+	return c.transformLast(transform{kind: transformFlipH})
+}
+
+// FlipV mirrors the layers added by the previous call top to bottom,
+// swapping glyphs like [CanvasStyle.FlipH].
+func (c *CanvasStyle) FlipV() *CanvasStyle {
+	return c.transformLast(transform{kind: transformFlipV})
+}
+
+// Rotate turns the layers added by the previous call by quarter turns
+// clockwise; negative values turn counterclockwise. Lines, corners and
+// junctions are remapped, so ─ becomes │ and ┌ becomes ┐. Other glyphs
+// keep their orientation: rotated text reads one letter per row.
+func (c *CanvasStyle) Rotate(turns int) *CanvasStyle {
+	return c.transformLast(transform{kind: transformRotate, turns: (turns%4 + 4) % 4})
+}
+
+// Scale enlarges the layers added by the previous call by integer
+// factors: each cell becomes an sx x sy block. Lines stay connected and
+// borders stay at the edges, so a scaled box is a bigger box, filling the
+// scaled area, rather than a blocky one. Factors below 1 count as 1.
+func (c *CanvasStyle) Scale(sx, sy int) *CanvasStyle {
+	if sx < 1 {
+		sx = 1
+	}
+	if sy < 1 {
+		sy = 1
+	}
+	return c.transformLast(transform{kind: transformScale, sx: sx, sy: sy})
+}
+
+// Crop cuts the rendered canvas to the w x h rectangle whose top-left
+// corner is at (x, y) of the output, after auto-sizing or the viewport
+// are applied.
+func (c *CanvasStyle) Crop(x, y, w, h int) *CanvasStyle {
+	cp := copyCanvas(c)
+	cp.crop = &rect{x, y, w, h}
+	return cp
+}
+
+// transformLast applies t to the layers added by the previous call and
+// records it, so that Replace applies it to new content too.
+func (c *CanvasStyle) transformLast(t transform) *CanvasStyle {
+	cp := copyCanvas(c)
+	for i := cp.lastAdd; i < len(cp.layers); i++ {
+		ly := &cp.layers[i]
+		ly.grid, ly.edges = applyTransform(t, ly.grid, ly.edges)
+		ly.transforms = append(ly.transforms[:len(ly.transforms):len(ly.transforms)], t)
+	}
+	return cp
+}
+
+// applyTransform returns transformed copies of a layer's grid and, for
+// line layers, its edges. Ragged rows are first padded to a rectangle
+// with see-through cells.
+func applyTransform(t transform, grid [][]cell, edges [][]lineEdges) ([][]cell, [][]lineEdges) {
+	h := len(grid)
+	w := 0
+	for _, row := range grid {
+		if len(row) > w {
+			w = len(row)
+		}
+	}
+	at := func(r, c int) (cell, lineEdges) {
+		var e lineEdges
+		if edges != nil {
+			e = edges[r][c]
+		}
+		if c >= len(grid[r]) {
+			return cell{r: ' ', clear: true}, e
+		}
+		return grid[r][c], e
+	}
+
+	newW, newH := w, h
+	switch t.kind {
+	case transformRotate:
+		if t.turns%2 == 1 {
+			newW, newH = h, w
+		}
+	case transformScale:
+		newW, newH = w*t.sx, h*t.sy
+	}
+	outGrid := make([][]cell, newH)
+	var outEdges [][]lineEdges
+	if edges != nil {
+		outEdges = make([][]lineEdges, newH)
+	}
+	for r := range outGrid {
+		outGrid[r] = make([]cell, newW)
+		if edges != nil {
+			outEdges[r] = make([]lineEdges, newW)
+		}
+	}
+	set := func(r, c int, cl cell, e lineEdges) {
+		outGrid[r][c] = cl
+		if edges != nil {
+			outEdges[r][c] = e
+		}
+	}
+
+	for r := 0; r < h; r++ {
+		for c := 0; c < w; c++ {
+			cl, e := at(r, c)
+			switch t.kind {
+			case transformFlipH:
+				cl.r = transformRune(cl.r, mirrorH, flipArmsH)
+				set(r, w-1-c, cl, flipArmsH(e))
+			case transformFlipV:
+				cl.r = transformRune(cl.r, mirrorV, flipArmsV)
+				set(h-1-r, c, cl, flipArmsV(e))
+			case transformRotate:
+				nr, nc := r, c
+				for i := 0; i < t.turns; i++ {
+					cl.r = transformRune(cl.r, rotateCW, rotateArms)
+					e = rotateArms(e)
+				}
+				switch t.turns {
+				case 1:
+					nr, nc = c, h-1-r
+				case 2:
+					nr, nc = h-1-r, w-1-c
+				case 3:
+					nr, nc = w-1-c, r
+				}
+				set(nr, nc, cl, e)
+			case transformScale:
+				at := [2]int{scaleOffset(c, w, t.sx), scaleOffset(r, h, t.sy)}
+				scaleCell(cl, e, t.sx, t.sy, at, func(dr, dc int, cl cell, e lineEdges) {
+					set(r*t.sy+dr, c*t.sx+dc, cl, e)
+				})
+			}
+		}
+	}
+	return outGrid, outEdges
+}
+
+// scaleOffset returns where, within its block of n cells, the line glyph
+// of cell i of size cells is placed when scaled. Offsets go from the start
+// of the first block to the end of the last, so a scaled frame fills the
+// whole area and its content stays centered.
+func scaleOffset(i, size, n int) int {
+	if size <= 1 {
+		return 0
+	}
+	return (2*i*(n-1) + size - 1) / (2 * (size - 1))
+}
+
+// scaleCell expands one cell into an sx x sy block. Box-drawing glyphs go
+// at offset at within the block and their arms are continued to the
+// block's edges, so lines stay connected; the rest of the block is blank.
+// Other cells are repeated.
+func scaleCell(cl cell, e lineEdges, sx, sy int, at [2]int, set func(dr, dc int, cl cell, e lineEdges)) {
+	src := e
+	if glyph, ok := edgesOf(cl.r); ok {
+		src = glyph
+	}
+	for dr := 0; dr < sy; dr++ {
+		for dc := 0; dc < sx; dc++ {
+			if src == (lineEdges{}) {
+				set(dr, dc, cl, e)
+				continue
+			}
+			if dr == at[1] && dc == at[0] {
+				set(dr, dc, cl, e)
+				continue
+			}
+			var arms lineEdges
+			switch {
+			case dr == at[1] && dc < at[0]:
+				arms = lineEdges{armLeft: src[armLeft], armRight: src[armLeft]}
+			case dr == at[1] && dc > at[0]:
+				arms = lineEdges{armLeft: src[armRight], armRight: src[armRight]}
+			case dc == at[0] && dr < at[1]:
+				arms = lineEdges{armUp: src[armUp], armDown: src[armUp]}
+			case dc == at[0] && dr > at[1]:
+				arms = lineEdges{armUp: src[armDown], armDown: src[armDown]}
+			}
+			fill := cl
+			fill.r = ' '
+			if arms == src {
+				// Straight lines keep their own glyph, such as a dashed one.
+				fill.r = cl.r
+			} else if g, ok := glyphFor(arms); ok && arms != (lineEdges{}) {
+				fill.r = g
+			}
+			// Line layers are drawn from their edges, text layers from
+			// their runes.
+			if e == (lineEdges{}) {
+				arms = lineEdges{}
+			}
+			set(dr, dc, fill, arms)
+		}
+	}
+}
+
+// cropGrid returns the w x h part of grid whose top-left corner is at
+// (x, y). Positions outside grid are see-through.
+func cropGrid(grid [][]cell, r rect) [][]cell {
+	if r.w <= 0 || r.h <= 0 {
+		return nil
+	}
+	out := make([][]cell, r.h)
+	for row := range out {
+		out[row] = make([]cell, r.w)
+		for col := range out[row] {
+			sy, sx := r.y+row, r.x+col
+			if sy >= 0 && sy < len(grid) && sx >= 0 && sx < len(grid[sy]) {
+				out[row][col] = grid[sy][sx]
+			} else {
+				out[row][col] = cell{r: ' ', clear: true}
+			}
+		}
+	}
+	return out
+}
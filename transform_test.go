@@ -0,0 +1,105 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasTransforms(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	box := "╭──┐\n│ab├─\n└──╯"
+
+	t.Run("flip horizontally mirrors glyphs", func(t *testing.T) {
+		got := Canvas().Add(box, 0, 0).FlipH().String()
+		assert.Equal(t, " ┌──╮\n─┤ba│\n ╰──┘", got)
+		assert.Equal(t, "] \\ )", Canvas().Add("( / [", 0, 0).FlipH().String())
+	})
+
+	t.Run("flip vertically mirrors glyphs", func(t *testing.T) {
+		got := Canvas().Add(box, 0, 0).FlipV().String()
+		assert.Equal(t, "┌──╮\n│ab├─\n╰──┘", got)
+		assert.Equal(t, "▄\n▀", Canvas().Add("▄\n▀", 0, 0).FlipV().FlipV().FlipV().FlipV().String())
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		assert.Equal(t, "┌─╮\n│a│\n│b│\n╰┬┘\n │", Canvas().Add(box, 0, 0).Rotate(1).String())
+		assert.Equal(t, " │\n┌┴╮\n│b│\n│a│\n╰─┘", Canvas().Add(box, 0, 0).Rotate(-1).String())
+		assert.Equal(t, " ╭──┐\n─┤ba│\n └──╯", Canvas().Add(box, 0, 0).Rotate(2).String())
+		assert.Equal(t, box, Canvas().Add(box, 0, 0).Rotate(4).String())
+	})
+
+	t.Run("rotated label", func(t *testing.T) {
+		got := Canvas().Add("Axis", 0, 0).Rotate(1).Add("graph", 2, 1).String()
+		assert.Equal(t, "A\nx graph\ni\ns", got)
+	})
+
+	t.Run("scaling keeps lines connected", func(t *testing.T) {
+		got := Canvas().Rect(0, 0, 3, 3, LineRounded).Scale(2, 1).String()
+		assert.Equal(t, "╭────╮\n│    │\n╰────╯", got)
+		assert.Equal(t, "┌────┐\n│    │\n│    │\n└────┘", Canvas().Add("┌┐\n└┘", 0, 0).Scale(3, 2).String())
+		assert.Equal(t, "┄┄┄┄", Canvas().Add("┄┄", 0, 0).Scale(2, 1).String())
+	})
+
+	t.Run("scaled box fills the scaled area", func(t *testing.T) {
+		c := Canvas().AddNamed("box", "┌──┐\n│ab│\n└──┘", 0, 0).Scale(2, 2)
+		want := strings.Join([]string{
+			"┌──────┐",
+			"│      │",
+			"│ aabb │",
+			"│ aabb │",
+			"│      │",
+			"└──────┘",
+		}, "\n")
+		assert.Equal(t, want, c.String())
+
+		_, _, w, h, _ := c.Bounds("box")
+		assert.Equal(t, [2]int{8, 6}, [2]int{w, h})
+	})
+
+	t.Run("scaling repeats other cells", func(t *testing.T) {
+		assert.Equal(t, "██▀▀\n██▀▀\n▄▄\n▄▄", Canvas().Add("█▀\n▄", 0, 0).Scale(2, 2).String())
+	})
+
+	t.Run("transformed lines merge with lines below", func(t *testing.T) {
+		got := Canvas().HLine(0, 1, 3, LineLight).Line(0, 0, 2, 2, LineHeavy).FlipH().String()
+		assert.Equal(t, "┏━━\n┠──\n┃", got)
+	})
+
+	t.Run("replace reapplies transforms", func(t *testing.T) {
+		got := Canvas().AddNamed("t", "ab", 0, 0).Rotate(1).Replace("t", "xyz").String()
+		assert.Equal(t, "x\ny\nz", got)
+	})
+
+	t.Run("only the previous call is transformed", func(t *testing.T) {
+		got := Canvas().Add("ab", 0, 0).Add("cd", 0, 1).FlipH().String()
+		assert.Equal(t, "ab\ndc", got)
+	})
+}
+
+func TestCanvasCrop(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	grid := Canvas().Add("abcd\nefgh\nijkl", 0, 0)
+
+	t.Run("cuts the output", func(t *testing.T) {
+		assert.Equal(t, "fg\njk", grid.Crop(1, 1, 2, 2).String())
+	})
+
+	t.Run("past the content is blank", func(t *testing.T) {
+		assert.Equal(t, "l\n", grid.Crop(3, 2, 2, 2).String())
+	})
+
+	t.Run("output coordinates after the origin shift", func(t *testing.T) {
+		got := Canvas().Add("xy", -2, 0).Add("z", 1, 0).Crop(0, 0, 2, 1).String()
+		assert.Equal(t, "xy", got)
+	})
+
+	t.Run("cell lookups use canvas coordinates", func(t *testing.T) {
+		assert.Equal(t, 'g', grid.Crop(1, 1, 2, 2).Cell(2, 1).Rune)
+	})
+}
@@ -0,0 +1,34 @@
+package tinta
+
+// rect is a rectangle in canvas coordinates.
+type rect struct {
+	x, y, w, h int
+}
+
+func (r *rect) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.w && y >= r.y && y < r.y+r.h
+}
+
+// Viewport renders only the w x h window of the canvas whose top-left
+// corner is at (x, y), in the coordinates used by [CanvasStyle.Add]. The
+// canvas becomes a virtual surface of any size: content outside the
+// window is cut off, the window is not shifted to fit negative positions,
+// and Width and Height are ignored. Moving the window scrolls the view.
+func (c *CanvasStyle) Viewport(x, y, w, h int) *CanvasStyle {
+	cp := copyCanvas(c)
+	cp.viewport = &rect{x, y, w, h}
+	return cp
+}
+
+// Clip restricts the layers added by the previous call to the w x h
+// rectangle whose top-left corner is at (x, y), in the coordinates used
+// by [CanvasStyle.Add]. Cells outside it are not drawn and do not count
+// toward the auto-sized canvas, so content added inside a panel, such as
+// a scrolled log, cannot spill out of it.
+func (c *CanvasStyle) Clip(x, y, w, h int) *CanvasStyle {
+	cp := copyCanvas(c)
+	for i := cp.lastAdd; i < len(cp.layers); i++ {
+		cp.layers[i].clip = &rect{x, y, w, h}
+	}
+	return cp
+}
@@ -0,0 +1,69 @@
+package tinta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/varavelio/tinta/internal/assert"
+)
+
+func TestCanvasViewport(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	world := Canvas().Add("abcdef\nghijkl\nmnopqr", 0, 0)
+
+	t.Run("window into the canvas", func(t *testing.T) {
+		assert.Equal(t, "hij\nnop", world.Viewport(1, 1, 3, 2).String())
+	})
+
+	t.Run("window past the content is blank", func(t *testing.T) {
+		assert.Equal(t, "ef\nkl\nqr\n", world.Viewport(4, 0, 3, 4).String())
+	})
+
+	t.Run("negative positions are not shifted", func(t *testing.T) {
+		got := Canvas().Add("xy", -1, 0).Add("z", 2, 0).Viewport(0, 0, 3, 1).String()
+		assert.Equal(t, "y z", got)
+	})
+
+	t.Run("scrolling", func(t *testing.T) {
+		lines := make([]string, 10)
+		for i := range lines {
+			lines[i] = string(rune('0' + i))
+		}
+		log := Canvas().Add(strings.Join(lines, "\n"), 0, 0)
+		assert.Equal(t, "0\n1", log.Viewport(0, 0, 1, 2).String())
+		assert.Equal(t, "7\n8", log.Viewport(0, 7, 1, 2).String())
+	})
+
+	t.Run("empty canvas", func(t *testing.T) {
+		assert.Equal(t, "", Canvas().Viewport(0, 0, 3, 2).String())
+	})
+}
+
+func TestCanvasClip(t *testing.T) {
+	ForceColors(false)
+	defer ForceColors(true)
+
+	panel := Box().String("    \n    ")
+
+	t.Run("layer is cut to the clip rectangle", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add("1\n2\n3\n4", 1, 0).Clip(1, 1, 4, 2).String()
+		assert.Equal(t, "┌────┐\n│2   │\n│3   │\n└────┘", got)
+	})
+
+	t.Run("scrolled content stays inside", func(t *testing.T) {
+		got := Canvas().Add(panel, 0, 0).Add("abcdefgh\nijklmnop", -1, 1).Clip(1, 1, 4, 2).String()
+		assert.Equal(t, "┌────┐\n│cdef│\n│klmn│\n└────┘", got)
+	})
+
+	t.Run("clipped cells do not size the canvas", func(t *testing.T) {
+		got := Canvas().Add("abcdef", 0, 0).Clip(0, 0, 2, 1).String()
+		assert.Equal(t, "ab", got)
+	})
+
+	t.Run("clip applies to the previous call only", func(t *testing.T) {
+		got := Canvas().Add("abc", 0, 0).Clip(0, 0, 1, 1).Add("xyz", 0, 1).String()
+		assert.Equal(t, "a\nxyz", got)
+	})
+}